@@ -0,0 +1,397 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// The curve module implements ECDSA over the short Weierstrass
+// curves whose parameters are defined in the sibling secp256k1,
+// p256, p384 and p521 modules.
+
+package curve
+
+import (
+	"encoding/asn1"
+	"errors"
+	"godot/ecdsa/prime"
+	"godot/rand"
+	"godot/sha256"
+	"math/big"
+)
+
+// The domain parameters of a curve of the form y^2 = x^3 + a*x + b
+// over a prime field of order p, with a base point G of order n.
+type Params struct {
+	Name   string                // as understood by --curve
+	OID    asn1.ObjectIdentifier // as found in SEC1 and X.509 keys
+	P      *big.Int              // order of the prime field
+	N      *big.Int              // order of the base point G
+	A, B   *big.Int              // coefficients
+	Gx, Gy *big.Int              // coordinates of G
+}
+
+// ByteLen() returns the length in bytes of an element of the prime
+// field over which the curve is defined.
+func (cp *Params) ByteLen() int {
+	return (cp.P.BitLen() + 7) / 8
+}
+
+// ScalarLen() returns the length in bytes of an integer modulo n.
+func (cp *Params) ScalarLen() int {
+	return (cp.N.BitLen() + 7) / 8
+}
+
+// newPoint() returns the point (x,y) of a curve c over a field f. It
+// fails if x or y are out of range, or if (x,y) is not on the curve.
+func newPoint(f *prime.Field, c *prime.Curve, x, y *big.Int) (*prime.Point,
+    error) {
+	xF, err := f.CheckedElement(x)
+	if err != nil {
+		return nil, err
+	}
+	yF, err := f.CheckedElement(y)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewPoint().CheckedSet(xF, yF)
+}
+
+// Get() instantiates the curve's parameters (field, curve, and base
+// point). It fails if the parameters are invalid.
+func (cp *Params) Get() (*prime.Field, *prime.Curve, *prime.Point,
+    error) {
+	f, err := new(prime.Field).CheckedSetOrder(cp.P)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c, err := new(prime.Curve).CheckedDefine(f, cp.A, cp.B)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	g, err := newPoint(f, c, cp.Gx, cp.Gy)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return f, c, g, nil
+}
+
+// scalarField() uses the fact that n is prime to define an ephemeral
+// field in which to perform modulo arithmetic.
+func (cp *Params) scalarField() (*prime.Field, error) {
+	return new(prime.Field).CheckedSetOrder(cp.N)
+}
+
+// Decompress() returns the y-coordinate of the point whose
+// x-coordinate is x, picking the odd root if odd is 1 and the even one
+// otherwise. It is used to decode compressed points (SEC1, section
+// 2.3.4).
+func (cp *Params) Decompress(x *big.Int, odd uint) (*big.Int, error) {
+	f, c, _, err := cp.Get()
+	if err != nil {
+		return nil, err
+	}
+	xF, err := f.CheckedElement(x)
+	if err != nil {
+		return nil, errors.New("invalid point")
+	}
+	q := c.NewPoint().SetX(xF, odd)
+	if q == nil {
+		return nil, errors.New("invalid point")
+	}
+
+	return q.GetY(), nil
+}
+
+// hashToInt() converts a digest h to an integer as specified in SEC1,
+// section 4.1.3, step 5: only the leftmost bits of h that fit in n are
+// kept.
+func (cp *Params) hashToInt(h []byte) *big.Int {
+	nBits := cp.N.BitLen()
+	if len(h) > cp.ScalarLen() {
+		h = h[:cp.ScalarLen()]
+	}
+	e := new(big.Int).SetBytes(h)
+	if excess := len(h) * 8 - nBits; excess > 0 {
+		e.Rsh(e, uint(excess))
+	}
+	return e
+}
+
+// NewPair() returns a new key pair (q,d).
+func (cp *Params) NewPair() (*prime.Point, *big.Int, error) {
+	var d *big.Int
+	var err error
+
+	for {
+		// rand.Int() returns an integer in [0,n).
+		// we want d in [1,n).
+		d, err = rand.Int(cp.N)
+		if err != nil {
+			return nil, nil, err
+		} else if d.Cmp(big.NewInt(0)) == 1 {
+			break
+		}
+	}
+
+	q, err := cp.Public(d)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return q, d, nil
+}
+
+// Public() returns the public point q = d*G of a private key d.
+func (cp *Params) Public(d *big.Int) (*prime.Point, error) {
+	if d.Sign() != 1 || d.Cmp(cp.N) != -1 {
+		return nil, errors.New("invalid private key")
+	}
+	_, c, g, err := cp.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.NewPoint().MulSecret(g, d, cp.N), nil
+}
+
+// IsOnCurve() checks whether (x,y) is a point on the curve. Both
+// coordinates must be in [0,p).
+func (cp *Params) IsOnCurve(x, y *big.Int) bool {
+	f, c, _, err := cp.Get()
+	if err != nil {
+		return false
+	}
+	xF, err := f.CheckedElement(x)
+	if err != nil {
+		return false
+	}
+	yF, err := f.CheckedElement(y)
+	if err != nil {
+		return false
+	}
+	return c.IsOnCurve(xF, yF)
+}
+
+// HasOrderN() checks whether n*(x,y) is the point at infinity, where n
+// is the order of G. (x,y) must be on the curve.
+func (cp *Params) HasOrderN(x, y *big.Int) bool {
+	f, c, _, err := cp.Get()
+	if err != nil {
+		return false
+	}
+	q, err := newPoint(f, c, x, y)
+	if err != nil {
+		return false
+	}
+	return c.IsInf(c.NewPoint().Mul(q, cp.N))
+}
+
+// Sign() generates a signature (r,s) of a digest h with a private key
+// d. The nonce is derived from d and h as specified in RFC 6979, so
+// that signing the same digest twice yields the same signature. If
+// extra is not nil, it is mixed into the nonce as additional data.
+func (cp *Params) Sign(h []byte, d *big.Int, extra []byte) (*big.Int,
+    *big.Int, error) {
+	r, s, _, err := cp.SignRecoverable(h, d, extra)
+	return r, s, err
+}
+
+// SignRecoverable() is like Sign(), but also returns the recovery id v
+// of the signature, from which Recover() can find the public key. Bit 0
+// of v is the parity of the y-coordinate of the nonce point R, and bit
+// 1 is set if the x-coordinate of R is not smaller than n. In order to
+// prevent malleability, s is always chosen to be in [1,n/2], as both
+// (r,s) and (r,n-s) are valid signatures.
+func (cp *Params) SignRecoverable(h []byte, d *big.Int,
+    extra []byte) (*big.Int, *big.Int, byte, error) {
+	if len(h) != sha256.Len {
+		return nil, nil, 0, errors.New("invalid hash length")
+	}
+
+	g, err := cp.newNonce(d, h, extra)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	n := cp.N
+	e := cp.hashToInt(h)
+	e.Mod(e, n)
+
+	_, c, G, err := cp.Get()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	f, err := cp.scalarField()
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	dF, err := f.CheckedElement(d)
+	if err != nil || d.Sign() == 0 {
+		return nil, nil, 0, errors.New("invalid private key")
+	}
+	eF, err := f.CheckedElement(e)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	for {
+		k, err := g.next()
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		Gk := c.NewPoint().MulSecret(G, k, n)
+		r := new(big.Int).Mod(Gk.GetX(), n)
+		if r.Sign() == 0 {
+			continue
+		}
+		kF, err := f.CheckedElement(k)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		rF, err := f.CheckedElement(r)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+
+		s := f.NewElement().Mul(dF, rF)
+		s.Add(s, eF)
+		_, err = s.CheckedDiv(s, kF)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		if s.GetValue().Sign() != 0 {
+			v := byte(Gk.GetY().Bit(0))
+			if Gk.GetX().Cmp(n) != -1 {
+				v |= 2
+			}
+			if cp.IsLowS(s.GetValue()) == false {
+				// -s corresponds to -k, and thus to -R.
+				s = f.NewElement().Neg(s)
+				v ^= 1
+			}
+			return r, s.GetValue(), v, nil
+		}
+	}
+}
+
+// IsLowS() checks whether s is in the lower half of [1,n-1], as
+// required by BIP-62 and BIP-146.
+func (cp *Params) IsLowS(s *big.Int) bool {
+	return s.Cmp(new(big.Int).Rsh(cp.N, 1)) != 1
+}
+
+func (cp *Params) Verify(qX, qY, r, s *big.Int, h []byte) (*big.Int,
+    error) {
+	var n = cp.N
+	if r.Cmp(big.NewInt(0)) != 1 || r.Cmp(n) != -1 ||
+	   s.Cmp(big.NewInt(0)) != 1 || s.Cmp(n) != -1 {
+		// r and s must be in the interval [1,n-1].
+		return nil, errors.New("invalid signature")
+	}
+
+	pF, c, g, err := cp.Get()
+	if err != nil {
+		return nil, err
+	}
+	f, err := cp.scalarField()
+	if err != nil {
+		return nil, err
+	}
+	q, err := newPoint(pF, c, qX, qY)
+	if err != nil {
+		return nil, err
+	}
+	e := cp.hashToInt(h)
+	sF, err := f.CheckedElement(s)
+	if err != nil {
+		return nil, err
+	}
+	eF, err := f.CheckedElement(e.Mod(e, n))
+	if err != nil {
+		return nil, err
+	}
+	rF, err := f.CheckedElement(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// The actual signature verification. All scalars involved are
+	// public, so the faster, variable-time multiplication is used.
+	wF, err := f.NewElement().CheckedDiv(f.Int64(1), sF)
+	if err != nil {
+		return nil, err
+	}
+	u1 := f.NewElement().Mul(eF, wF)
+	u2 := f.NewElement().Mul(rF, wF)
+	A := c.NewPoint().Mul(g, u1.GetValue())
+	B := c.NewPoint().Mul(q, u2.GetValue())
+	C := c.NewPoint().Add(A, B)
+	if c.IsInf(C) {
+		return nil, errors.New("invalid signature")
+	}
+	v := C.GetX()
+
+	return v.Mod(v, n), nil
+}
+
+// Recover() recovers the public key q from a signature (r,s) of a
+// digest h and its recovery id v, as specified in SEC1, section 4.1.6.
+func (cp *Params) Recover(r, s *big.Int, v byte, h []byte) (*big.Int,
+    *big.Int, error) {
+	var n = cp.N
+	if r.Cmp(big.NewInt(0)) != 1 || r.Cmp(n) != -1 ||
+	   s.Cmp(big.NewInt(0)) != 1 || s.Cmp(n) != -1 || v > 3 {
+		return nil, nil, errors.New("invalid signature")
+	}
+
+	// Reconstruct the nonce point R from r and v.
+	pF, c, g, err := cp.Get()
+	if err != nil {
+		return nil, nil, err
+	}
+	x := new(big.Int).Set(r)
+	if v & 2 != 0 {
+		x.Add(x, n)
+	}
+	if x.Cmp(cp.P) != -1 {
+		return nil, nil, errors.New("invalid signature")
+	}
+	xF, err := pF.CheckedElement(x)
+	if err != nil {
+		return nil, nil, err
+	}
+	R := c.NewPoint().SetX(xF, uint(v & 1))
+	if R == nil {
+		return nil, nil, errors.New("invalid signature")
+	}
+
+	// q = r^-1 * (s*R - e*G). All scalars involved are public.
+	f, err := cp.scalarField()
+	if err != nil {
+		return nil, nil, err
+	}
+	e := cp.hashToInt(h)
+	eF, err := f.CheckedElement(e.Mod(e, n))
+	if err != nil {
+		return nil, nil, err
+	}
+	rF, err := f.CheckedElement(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	sF, err := f.CheckedElement(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	wF, err := f.NewElement().CheckedDiv(f.Int64(1), rF)
+	if err != nil {
+		return nil, nil, err
+	}
+	u1 := f.NewElement().Mul(f.NewElement().Neg(eF), wF)
+	u2 := f.NewElement().Mul(sF, wF)
+	A := c.NewPoint().Mul(g, u1.GetValue())
+	B := c.NewPoint().Mul(R, u2.GetValue())
+	q := c.NewPoint().Add(A, B)
+	if c.IsInf(q) {
+		return nil, nil, errors.New("invalid signature")
+	}
+
+	return q.GetX(), q.GetY(), nil
+}
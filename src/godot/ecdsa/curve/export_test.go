@@ -0,0 +1,21 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package curve
+
+import (
+	"math/big"
+)
+
+// Nonce() exposes the first RFC 6979 nonce for a private key d and a
+// digest h to the tests, which live in curve_test so that they can
+// import the curves.
+func (cp *Params) Nonce(d *big.Int, h []byte) (*big.Int, error) {
+	g, err := cp.newNonce(d, h, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.next()
+}
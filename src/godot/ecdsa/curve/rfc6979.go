@@ -0,0 +1,106 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// rfc6979.go implements the deterministic generation of ECDSA nonces
+// specified in RFC 6979, section 3.2, with HMAC-SHA256 as the
+// underlying HMAC_DRBG.
+
+package curve
+
+import (
+	"godot/hmac"
+	"math/big"
+)
+
+// The state of an HMAC_DRBG instance, as used in section 3.2.
+type nonce struct {
+	cp      *Params
+	k, v    []byte
+	started bool
+}
+
+// int2octets() implements section 2.3.3.
+func (cp *Params) int2octets(x *big.Int) []byte {
+	b := x.Bytes()
+	p := make([]byte, cp.ScalarLen())
+	copy(p[len(p) - len(b):], b)
+	return p
+}
+
+// bits2octets() implements section 2.3.4.
+func (cp *Params) bits2octets(h []byte) []byte {
+	z := cp.hashToInt(h)
+	return cp.int2octets(z.Mod(z, cp.N))
+}
+
+// newNonce() seeds a nonce generator with a private key d and a digest
+// h (section 3.2, steps a through g). If extra is not nil, it is mixed
+// in as additional data, as described in section 3.6.
+func (cp *Params) newNonce(d *big.Int, h, extra []byte) (*nonce, error) {
+	var err error
+
+	g := &nonce{cp: cp}
+	g.v = make([]byte, 32)
+	g.k = make([]byte, 32)
+	for i := range g.v {
+		g.v[i] = 0x01
+	}
+	x := cp.int2octets(d)
+	h1 := cp.bits2octets(h)
+
+	for _, sep := range []byte{0x00, 0x01} {
+		// K = HMAC_K(V || sep || int2octets(x) ||
+		//     bits2octets(h1) || extra)
+		m := append(append([]byte{}, g.v...), sep)
+		m = append(append(append(m, x...), h1...), extra...)
+		g.k, err = hmac.SHA256(g.k, m)
+		if err != nil {
+			return nil, err
+		}
+		// V = HMAC_K(V)
+		g.v, err = hmac.SHA256(g.k, g.v)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// next() returns the next candidate nonce k in [1,n) (section 3.2,
+// step h). Each call after the first one discards the previous
+// candidate as per step h.3, so that callers may keep asking for
+// nonces until one yields a valid signature.
+func (g *nonce) next() (*big.Int, error) {
+	var err error
+
+	cp := g.cp
+	for {
+		if g.started {
+			m := append(append([]byte{}, g.v...), 0x00)
+			g.k, err = hmac.SHA256(g.k, m)
+			if err != nil {
+				return nil, err
+			}
+			g.v, err = hmac.SHA256(g.k, g.v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		g.started = true
+
+		var t []byte
+		for len(t) * 8 < cp.N.BitLen() {
+			g.v, err = hmac.SHA256(g.k, g.v)
+			if err != nil {
+				return nil, err
+			}
+			t = append(t, g.v...)
+		}
+		k := cp.hashToInt(t)
+		if k.Sign() == 1 && k.Cmp(cp.N) == -1 {
+			return k, nil
+		}
+	}
+}
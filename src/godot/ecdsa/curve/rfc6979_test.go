@@ -0,0 +1,170 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package curve_test
+
+import (
+	"godot/ecdsa/curve"
+	"godot/ecdsa/p256"
+	"godot/ecdsa/p384"
+	"godot/ecdsa/p521"
+	"godot/ecdsa/secp256k1"
+	"godot/sha256"
+	"math/big"
+	"testing"
+)
+
+// The SHA-256 test vectors of RFC 6979, appendices A.2.5 to A.2.7,
+// followed by secp256k1 vectors in common use, as RFC 6979 has none
+// for that curve. The values of s are those of the plain algorithm.
+var vectors = []struct {
+	cp      *curve.Params
+	x, m    string
+	k, r, s string
+}{
+	{
+		p256.Params,
+		"C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721",
+		"sample",
+		"A6E3C57DD01ABE90086538398355DD4C3B17AA873382B0F24D6129493D8AAD60",
+		"EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716",
+		"F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8",
+	},
+	{
+		p256.Params,
+		"C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721",
+		"test",
+		"D16B6AE827F17175E040871A1C7EC3500192C4C92677336EC2537ACAEE0008E0",
+		"F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367",
+		"019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083",
+	},
+	{
+		p384.Params,
+		"6B9D3DAD2E1B8C1C05B19875B6659F4DE23C3B667BF297BA" +
+		"9AA47740787137D896D5724E4C70A825F872C9EA60D2EDF5",
+		"sample",
+		"180AE9F9AEC5438A44BC159A1FCB277C7BE54FA20E7CF404" +
+		"B490650A8ACC414E375572342863C899F9F2EDF9747A9B60",
+		"21B13D1E013C7FA1392D03C5F99AF8B30C570C6F98D4EA8E" +
+		"354B63A21D3DAA33BDE1E888E63355D92FA2B3C36D8FB2CD",
+		"F3AA443FB107745BF4BD77CB3891674632068A10CA67E3D4" +
+		"5DB2266FA7D1FEEBEFDC63ECCD1AC42EC0CB8668A4FA0AB0",
+	},
+	{
+		p384.Params,
+		"6B9D3DAD2E1B8C1C05B19875B6659F4DE23C3B667BF297BA" +
+		"9AA47740787137D896D5724E4C70A825F872C9EA60D2EDF5",
+		"test",
+		"0CFAC37587532347DC3389FDC98286BBA8C73807285B184C" +
+		"83E62E26C401C0FAA48DD070BA79921A3457ABFF2D630AD7",
+		"6D6DEFAC9AB64DABAFE36C6BF510352A4CC27001263638E5" +
+		"B16D9BB51D451559F918EEDAF2293BE5B475CC8F0188636B",
+		"2D46F3BECBCC523D5F1A1256BF0C9B024D879BA9E838144C" +
+		"8BA6BAEB4B53B47D51AB373F9845C0514EEFB14024787265",
+	},
+	{
+		p521.Params,
+		"0FAD06DAA62BA3B25D2FB40133DA757205DE67F5BB00" +
+		"18FEE8C86E1B68C7E75CAA896EB32F1F47C70855836A" +
+		"6D16FCC1466F6D8FBEC67DB89EC0C08B0E996B83538",
+		"sample",
+		"0EDF38AFCAAECAB4383358B34D67C9F2216C8382AAEA" +
+		"44A3DAD5FDC9C32575761793FEF24EB0FC276DFC4F6E" +
+		"3EC476752F043CF01415387470BCBD8678ED2C7E1A0",
+		"1511BB4D675114FE266FC4372B87682BAECC01D3CC62" +
+		"CF2303C92B3526012659D16876E25C7C1E57648F23B7" +
+		"3564D67F61C6F14D527D54972810421E7D87589E1A7",
+		"04A171143A83163D6DF460AAF61522695F207A58B95C" +
+		"0644D87E52AA1A347916E4F7A72930B1BC06DBE22CE3" +
+		"F58264AFD23704CBB63B29B931F7DE6C9D949A7ECFC",
+	},
+	{
+		p521.Params,
+		"0FAD06DAA62BA3B25D2FB40133DA757205DE67F5BB00" +
+		"18FEE8C86E1B68C7E75CAA896EB32F1F47C70855836A" +
+		"6D16FCC1466F6D8FBEC67DB89EC0C08B0E996B83538",
+		"test",
+		"01DE74955EFAABC4C4F17F8E84D881D1310B5392D770" +
+		"0275F82F145C61E843841AF09035BF7A6210F5A431A6" +
+		"A9E81C9323354A9E69135D44EBD2FCAA7731B909258",
+		"00E871C4A14F993C6C7369501900C4BC1E9C7B0B4BA4" +
+		"4E04868B30B41D8071042EB28C4C250411D0CE08CD19" +
+		"7E4188EA4876F279F90B3D8D74A3C76E6F1E4656AA8",
+		"0CD52DBAA33B063C3A6CD8058A1FB0A46A4754B034FC" +
+		"C644766CA14DA8CA5CA9FDE00E88C1AD60CCBA759025" +
+		"299079D7A427EC3CC5B619BFBC828E7769BCD694E86",
+	},
+	{
+		secp256k1.Params,
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"Satoshi Nakamoto",
+		"8F8A276C19F4149656B280621E358CCE24F5F52542772691EE69063B74F15D15",
+		"934B1EA10A4B3C1757E2B0C017D0B6143CE3C9A7E6A4A49860D7A6AB210EE3D8",
+		"DBBD3162D46E9F9BEF7FEB87C16DC13B4F6568A87F4E83F728E2443BA586675C",
+	},
+	{
+		secp256k1.Params,
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"All those moments will be lost in time, like tears in rain. " +
+		"Time to die...",
+		"38AA22D72376B4DBC472E06C3BA403EE0A394DA63FC58D88686C611ABA98D6B3",
+		"8600DBD41E348FE5C9465AB92D23E3DB8B98B873BEECD930736488696438CB6B",
+		"AB8019BBD8B6924CC4099FE625340FFB1EAAC34BF4477DAA39D0835429094520",
+	},
+	{
+		secp256k1.Params,
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364140",
+		"Satoshi Nakamoto",
+		"33A19B60E25FB6F4435AF53A3D42D493644827367E6453928554F43E49AA6F90",
+		"FD567D121DB66E382991534ADA77A6BD3106F0A1098C231E47993447CD6AF2D0",
+		"94C632F14E4379FC1EA610A3DF5A375152549736425EE17CEBE10ABBC2A2826C",
+	},
+	{
+		secp256k1.Params,
+		"F8B8AF8CE3C7CCA5E300D33939540C10D45CE001B8F252BFBC57BA0342904181",
+		"Alan Turing",
+		"525A82B70E67874398067543FD84C83D30C175FDC45FDEEE082FE13B1D7CFDF1",
+		"7063AE83E7F62BBB171798131B4A0564B956930092B33B07B395615D9EC7E15C",
+		"A72033E1FF5CA1EA8D0C99001CB45F0272D3BE7525D3049C0D9E98DC7582B857",
+	},
+}
+
+func hexInt(t *testing.T, s string) *big.Int {
+	x, ok := new(big.Int).SetString(s, 16)
+	if ok == false {
+		t.Fatalf("bad hex %s", s)
+	}
+
+	return x
+}
+
+func TestRFC6979(t *testing.T) {
+	for i, v := range vectors {
+		cp := v.cp
+		d := hexInt(t, v.x)
+		h, err := sha256.DigestBytes([]byte(v.m))
+		if err != nil {
+			t.Fatal(err)
+		}
+		k, err := cp.Nonce(d, h)
+		if err != nil {
+			t.Fatalf("vector %d: %v", i, err)
+		}
+		if k.Cmp(hexInt(t, v.k)) != 0 {
+			t.Errorf("vector %d: k mismatch", i)
+		}
+		r, s, err := cp.Sign(h, d, nil)
+		if err != nil {
+			t.Fatalf("vector %d: %v", i, err)
+		}
+		// Sign() normalises s to the lower half of [1,n-1].
+		want := hexInt(t, v.s)
+		if cp.IsLowS(want) == false {
+			want.Sub(cp.N, want)
+		}
+		if r.Cmp(hexInt(t, v.r)) != 0 || s.Cmp(want) != 0 {
+			t.Errorf("vector %d: signature mismatch", i)
+		}
+	}
+}
@@ -5,95 +5,315 @@
 package ecdsa
 
 import (
+	"bytes"
+	"encoding/asn1"
 	"errors"
+	"fmt"
+	"godot/ecdsa/curve"
+	"godot/ecdsa/p256"
+	"godot/ecdsa/p384"
+	"godot/ecdsa/p521"
 	"godot/ecdsa/sec1"
 	"godot/ecdsa/secp256k1"
+	"godot/jwk"
+	"godot/rand"
 	"godot/sha256"
+	"godot/util"
 	"io"
+	"math/big"
+	"os"
 )
 
+// The curves we support. The first one is the default.
+var curves = []*curve.Params {
+	secp256k1.Params,
+	p256.Params,
+	p384.Params,
+	p521.Params,
+}
+
 type ecdsa struct {
-	Private *sec1.PrivateKey
-	Public  *sec1.PublicKey
+	Curve     *curve.Params
+	Private   *sec1.PrivateKey
+	Public    *sec1.PublicKey
+	randomize bool   // mix fresh randomness into nonces
+	recover   bool   // emit recoverable signatures
+	strict    bool   // reject high-S and non-canonical signatures
+	lax       bool   // accept them, even on secp256k1
+	compress  bool   // write compressed public keys
+	raw       bool   // encode signatures as r||s instead of DER
+	format    string // "sec1" (the default), "pkcs8" or "jwk"
+	pubFmt    string // "x509" (the default) or "jwk"
 }
 
 func New() *ecdsa {
-	return new(ecdsa)
+	return &ecdsa{Curve: curves[0], format: "sec1", pubFmt: "x509"}
+}
+
+// curveByName() looks up a curve by the name given to --curve.
+func curveByName(name string) (*curve.Params, error) {
+	for _, cp := range curves {
+		if cp.Name == name {
+			return cp, nil
+		}
+	}
+	return nil, errors.New("unsupported curve")
+}
+
+// curveByOID() looks up a curve by the OID found in a key.
+func curveByOID(id *asn1.ObjectIdentifier) (*curve.Params, error) {
+	for _, cp := range curves {
+		if cp.OID.Equal(*id) {
+			return cp, nil
+		}
+	}
+	return nil, errors.New("unsupported curve")
+}
+
+// Option() parses ECDSA-specific options.
+func (ec *ecdsa) Option(op string, args []string, i *int) bool {
+	var err error
+
+	switch {
+	case (op == "new" || op == "recover" || op == "sigconv") &&
+	    (args[*i] == "-c" || args[*i] == "--curve"):
+		ec.Curve, err = curveByName(util.GetArg(args, i))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	case op == "new" && (args[*i] == "-f" || args[*i] == "--format"):
+		err = ec.SetFormat(util.GetArg(args, i))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	case op == "sign" && (args[*i] == "-r" || args[*i] == "--randomize"):
+		ec.randomize = true
+	case op == "sign" && (args[*i] == "-R" || args[*i] == "--recoverable"):
+		ec.recover = true
+	case op == "pub" && (args[*i] == "-f" || args[*i] == "--format"):
+		ec.pubFmt = util.GetArg(args, i)
+		if ec.pubFmt != "x509" && ec.pubFmt != "jwk" {
+			fmt.Fprintf(os.Stderr, "unsupported format\n")
+			os.Exit(1)
+		}
+	case op == "pub" && args[*i] == "--compressed":
+		ec.compress = true
+	case op == "verify" && args[*i] == "--strict":
+		ec.strict = true
+	case op == "verify" && args[*i] == "--lax":
+		ec.lax = true
+	case (op == "sign" || op == "verify" || op == "sigconv") &&
+	    args[*i] == "--sig-encoding":
+		enc := util.GetArg(args, i)
+		if enc != "der" && enc != "raw" {
+			fmt.Fprintf(os.Stderr, "unsupported signature encoding\n")
+			os.Exit(1)
+		}
+		ec.raw = enc == "raw"
+	default:
+		return false
+	}
+
+	return true
 }
 
-// NewKey() creates a new secp256k1 key pair and writes it to w in PEM
-// format. The parameter l is ignored.
+// SetFormat() selects the form of new private keys: "sec1", "pkcs8" or
+// "jwk".
+func (ec *ecdsa) SetFormat(f string) error {
+	if f != "sec1" && f != "pkcs8" && f != "jwk" {
+		return errors.New("unsupported format")
+	}
+	ec.format = f
+
+	return nil
+}
+
+// Describe() returns the names of the signature algorithm and hash, as
+// recorded in armored signatures.
+func (ec *ecdsa) Describe() (alg, hash string) {
+	return "ECDSA", "SHA-256"
+}
+
+// JWSAlg() returns the JWS algorithm for the curve of the loaded key,
+// or an empty string if there is none. ES384 and ES512 would require
+// SHA-384 and SHA-512, so they are not supported.
+func (ec *ecdsa) JWSAlg() string {
+	switch ec.Curve {
+	case p256.Params:
+		return "ES256"
+	case secp256k1.Params:
+		return "ES256K"
+	default:
+		return ""
+	}
+}
+
+// SetJWS() configures signatures for the JWS algorithm alg, which
+// must match the curve of the loaded key. As per
+// https://tools.ietf.org/rfc/rfc7518.txt, section 3.4, signatures are
+// encoded as r||s. JWS does not mandate low-S signatures, so they are
+// not enforced on verification.
+func (ec *ecdsa) SetJWS(alg string) error {
+	if alg == "" || alg != ec.JWSAlg() {
+		return errors.New("unsupported algorithm for this key")
+	}
+	ec.raw = true
+	ec.lax = true
+	ec.recover = false
+
+	return nil
+}
+
+// NewKey() creates a new key pair on the selected curve and writes it
+// to w in PEM format. The key size is determined by the curve, so l
+// must be zero.
 func (ec *ecdsa) NewKey(l int, w io.Writer) error {
-	q, d, err := secp256k1.NewPair()
+	if l != 0 {
+		return errors.New("key size is determined by the curve")
+	}
+	cp := ec.Curve
+	q, d, err := cp.NewPair()
 	if err != nil {
 		return err
 	}
 	k := new(sec1.PrivateKey)
-	err = k.SetCurve(&secp256k1.OID)
+	err = k.SetCurve(&cp.OID)
+	if err != nil {
+		return err
+	}
+	err = k.SetPoint(q.GetX(), q.GetY(), cp.ByteLen())
 	if err != nil {
 		return err
 	}
-	err = k.SetPoint(q.GetX(), q.GetY())
+	err = k.SetGenerator(d, cp.ScalarLen())
 	if err != nil {
 		return err
 	}
-	k.SetGenerator(d)
 	ec.Private = k
-
-	return k.Write(w)
+	switch ec.format {
+	case "pkcs8":
+		return k.WritePKCS8(w)
+	case "jwk":
+		j, err := ec.toJWK(false)
+		if err != nil {
+			return err
+		}
+		return j.Write(w)
+	default:
+		return k.Write(w)
+	}
 }
 
-// LoadPriv() loads a private key from r.
+// LoadPriv() loads a private key from r, in SEC1, PKCS#8 or JWK form.
+// The curve is inferred from the key, which is then validated.
 func (ec *ecdsa) LoadPriv(r io.Reader) error {
-	k, err := new(sec1.PrivateKey).Read(r)
-	if err != nil {
-		return err
+	var k *sec1.PrivateKey
+
+	body := util.ReadAll(r)
+	if jwk.IsKey(body) {
+		j, err := jwk.Read(bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		ec.Curve, k, err = privFromJWK(j)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		k, err = new(sec1.PrivateKey).Read(bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		id, err := k.GetCurveID()
+		if err != nil {
+			return err
+		}
+		ec.Curve, err = curveByOID(id)
+		if err != nil {
+			return err
+		}
 	}
-	id, err := k.GetCurveID()
+	err := k.Validate(ec.Curve)
 	if err != nil {
 		return err
 	}
-	if secp256k1.OID.Equal(*id) == false {
-		return errors.New("unsupported curve")
-	}
 	ec.Private = k
 
 	return nil
 }
 
-// LoadPub() loads a public key from r.
+// LoadPub() loads a public key from r, in X.509 or JWK form. The curve
+// is inferred from the key, which is then validated.
 func (ec *ecdsa) LoadPub(r io.Reader) error {
-	k, err := new(sec1.PublicKey).Read(r)
+	var k *sec1.PublicKey
+
+	body := util.ReadAll(r)
+	if jwk.IsKey(body) {
+		j, err := jwk.Read(bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		ec.Curve, k, err = pointFromJWK(j)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		k, err = new(sec1.PublicKey).Read(bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		ec.Curve, err = curveByOID(k.GetCurveID())
+		if err != nil {
+			return err
+		}
+	}
+	err := k.Validate(ec.Curve)
 	if err != nil {
 		return err
 	}
-	id := k.GetCurveID()
-	if secp256k1.OID.Equal(*id) == false {
-		return errors.New("unsupported curve")
-	}
 	ec.Public = k
 
 	return nil
 }
 
-// WritePub() writes a public key to w.
+// WritePub() writes a public key to w, in X.509 form unless a JWK was
+// requested.
 func (ec *ecdsa) WritePub(w io.Writer) error {
+	if ec.pubFmt == "jwk" {
+		j, err := ec.toJWK(true)
+		if err != nil {
+			return err
+		}
+		return j.Write(w)
+	}
 	k := ec.Private
-	x, y, err := k.GetPoint()
+	x, y, err := k.GetPoint(ec.Curve)
 	if err != nil {
 		return err
 	}
-	pk, err := new(sec1.PublicKey).SetPoint(x, y)
+	pk := new(sec1.PublicKey)
+	if ec.compress {
+		_, err = pk.SetCompressed(x, y, ec.Curve.ByteLen())
+	} else {
+		_, err = pk.SetPoint(x, y, ec.Curve.ByteLen())
+	}
 	if err != nil {
 		return err
 	}
-	pk.SetCurve(&secp256k1.OID)
+	pk.SetCurve(&ec.Curve.OID)
 
 	return pk.Write(w)
 }
 
 // Sign() generates a signature of m and writes it to w.
 func (ec *ecdsa) Sign(m io.Reader, w io.Writer) error {
+	if ec.recover && ec.raw {
+		return errors.New("recoverable signatures cannot be raw")
+	}
 	k := ec.Private
 	d, err := k.GetGenerator()
 	if err != nil {
@@ -103,18 +323,161 @@ func (ec *ecdsa) Sign(m io.Reader, w io.Writer) error {
 	if err != nil {
 		return err
 	}
-	r, s, err := secp256k1.Sign(h, d)
+	var extra []byte
+	if ec.randomize {
+		extra, err = rand.Bytes(32)
+		if err != nil {
+			return err
+		}
+	}
+	r, s, v, err := ec.Curve.SignRecoverable(h, d, extra)
 	if err != nil {
 		return err
 	}
+	if ec.recover {
+		return writeRecoverable(r, s, v, ec.Curve.ScalarLen(), w)
+	}
+	if ec.raw {
+		return writeRaw(r, s, ec.Curve.ScalarLen(), w)
+	}
 
 	return new(sec1.Signature).Set(r, s).Write(w)
 }
 
+// writeRaw() writes a signature r||s to w, with r and s encoded in l
+// bytes each.
+func writeRaw(r, s *big.Int, l int, w io.Writer) error {
+	sig := make([]byte, 2 * l)
+	r.FillBytes(sig[:l])
+	s.FillBytes(sig[l:])
+	_, err := w.Write(sig)
+
+	return err
+}
+
+// readRaw() reads a signature r||s from t.
+func readRaw(t io.Reader, l int) (*sec1.Signature, error) {
+	sig := util.ReadAll(t)
+	if len(sig) != 2 * l {
+		return nil, errors.New("invalid signature length")
+	}
+	r := new(big.Int).SetBytes(sig[:l])
+	s := new(big.Int).SetBytes(sig[l:])
+
+	return new(sec1.Signature).Set(r, s), nil
+}
+
+// writeRecoverable() writes a recoverable signature r||s||v to w, with
+// r and s encoded in l bytes each and v in a single byte.
+func writeRecoverable(r, s *big.Int, v byte, l int, w io.Writer) error {
+	sig := make([]byte, 2 * l + 1)
+	r.FillBytes(sig[:l])
+	s.FillBytes(sig[l:2 * l])
+	sig[2 * l] = v
+	_, err := w.Write(sig)
+
+	return err
+}
+
+// readRecoverable() reads a recoverable signature r||s||v from t. As
+// is common in Ethereum, v may also be given as 27 + v.
+func readRecoverable(t io.Reader, l int) (*big.Int, *big.Int, byte,
+    error) {
+	sig := util.ReadAll(t)
+	if len(sig) != 2 * l + 1 {
+		return nil, nil, 0, errors.New("invalid signature length")
+	}
+	r := new(big.Int).SetBytes(sig[:l])
+	s := new(big.Int).SetBytes(sig[l:2 * l])
+	v := sig[2 * l]
+	if v >= 27 {
+		v -= 27
+	}
+
+	return r, s, v, nil
+}
+
+// Recover() recovers the public key from a recoverable signature t of m
+// and writes it to w.
+func (ec *ecdsa) Recover(t, m io.Reader, w io.Writer) error {
+	cp := ec.Curve
+	r, s, v, err := readRecoverable(t, cp.ScalarLen())
+	if err != nil {
+		return err
+	}
+	h, err := sha256.DigestAll(m)
+	if err != nil {
+		return err
+	}
+	x, y, err := cp.Recover(r, s, v, h)
+	if err != nil {
+		return err
+	}
+	pk, err := new(sec1.PublicKey).SetPoint(x, y, cp.ByteLen())
+	if err != nil {
+		return err
+	}
+	pk.SetCurve(&cp.OID)
+
+	return pk.Write(w)
+}
+
+// parseSig() parses a DER-encoded or raw signature on the curve cp.
+// The interpretations are tried in order, and the first one yielding r
+// and s in [1,n) is taken: strict DER, then lax DER if body looks like
+// a SEQUENCE, then raw if body has the length of r||s.
+func parseSig(cp *curve.Params, body []byte) (*sec1.Signature, error) {
+	l := cp.ScalarLen()
+	parsers := []func(io.Reader) (*sec1.Signature, error){
+		new(sec1.Signature).ReadStrict,
+	}
+	if len(body) != 0 && body[0] == 0x30 {
+		parsers = append(parsers, new(sec1.Signature).Read)
+	}
+	if len(body) == 2 * l {
+		parsers = append(parsers,
+		    func(t io.Reader) (*sec1.Signature, error) {
+			return readRaw(t, l)
+		})
+	}
+	for _, parse := range parsers {
+		sig, err := parse(bytes.NewReader(body))
+		if err == nil &&
+		   sig.R.Sign() == 1 && sig.R.Cmp(cp.N) == -1 &&
+		   sig.S.Sign() == 1 && sig.S.Cmp(cp.N) == -1 {
+			return sig, nil
+		}
+	}
+
+	return nil, errors.New("signature neither DER nor raw on the " +
+	    "selected curve")
+}
+
+// Convert() reads a DER-encoded or raw signature from t, normalises it
+// so that s is at most n/2, and writes it to w in the encoding selected
+// by --sig-encoding. No key is needed, but the signature must be on the
+// selected curve.
+func (ec *ecdsa) Convert(t io.Reader, w io.Writer) error {
+	cp := ec.Curve
+	sig, err := parseSig(cp, util.ReadAll(t))
+	if err != nil {
+		return err
+	}
+	s := sig.S
+	if cp.IsLowS(s) == false {
+		s = new(big.Int).Sub(cp.N, s)
+	}
+	if ec.raw {
+		return writeRaw(sig.R, s, cp.ScalarLen(), w)
+	}
+
+	return new(sec1.Signature).Set(sig.R, s).Write(w)
+}
+
 // Verify() checks if t is a valid signature of m.
 func (ec *ecdsa) Verify(t, m io.Reader) (bool, error) {
 	k := ec.Public
-	qX, qY, err := k.GetPoint()
+	qX, qY, err := k.GetPoint(ec.Curve)
 	if err != nil {
 		return false, err
 	}
@@ -122,11 +485,28 @@ func (ec *ecdsa) Verify(t, m io.Reader) (bool, error) {
 	if err != nil {
 		return false, err
 	}
-	sig, err := new(sec1.Signature).Read(t)
+	// Strict verification is the default on secp256k1, where
+	// malleable signatures are a known problem (BIP-62, BIP-146).
+	strict := ec.strict
+	if ec.Curve == secp256k1.Params && ec.lax == false {
+		strict = true
+	}
+	var sig *sec1.Signature
+	switch {
+	case ec.raw:
+		sig, err = readRaw(t, ec.Curve.ScalarLen())
+	case strict:
+		sig, err = new(sec1.Signature).ReadStrict(t)
+	default:
+		sig, err = new(sec1.Signature).Read(t)
+	}
 	if err != nil {
 		return false, err
 	}
-	v, err := secp256k1.Verify(qX, qY, sig.R, sig.S, h)
+	if strict && ec.Curve.IsLowS(sig.S) == false {
+		return false, nil
+	}
+	v, err := ec.Curve.Verify(qX, qY, sig.R, sig.S, h)
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,102 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package ecdsa
+
+import (
+	"bytes"
+	"encoding/hex"
+	"godot/ecdsa/p256"
+	"math/big"
+	"testing"
+)
+
+func decode(t *testing.T, s string) []byte {
+	p, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+// Signatures on P-256, whose r||s form is 64 bytes long.
+var sigs = []struct {
+	body string
+	r, s string // "" if body must be refused
+}{
+	{ // DER, 64 bytes long
+		"303e021d0102030405060708090a0b0c0d0e0f101112131415161718191a1b" +
+		"1c1d021d1d1c1b1a191817161514131211100f0e0d0c0b0a090807060504" +
+		"030201",
+		"0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d",
+		"1d1c1b1a191817161514131211100f0e0d0c0b0a090807060504030201",
+	},
+	{ // r||s
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"1",
+		"2",
+	},
+	{ // r||s starting with 0x30
+		"3000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002",
+		"3000000000000000000000000000000000000000000000000000000000000001",
+		"2",
+	},
+	{ // DER with r = 0
+		"3006020100020101",
+		"",
+		"",
+	},
+	{ // neither
+		"0102030405",
+		"",
+		"",
+	},
+}
+
+func TestParseSig(t *testing.T) {
+	for i, v := range sigs {
+		sig, err := parseSig(p256.Params, decode(t, v.body))
+		if v.r == "" {
+			if err == nil {
+				t.Errorf("sig %d: accepted", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("sig %d: %v", i, err)
+			continue
+		}
+		r, _ := new(big.Int).SetString(v.r, 16)
+		s, _ := new(big.Int).SetString(v.s, 16)
+		if sig.R.Cmp(r) != 0 || sig.S.Cmp(s) != 0 {
+			t.Errorf("sig %d: got (%x,%x)", i, sig.R, sig.S)
+		}
+	}
+}
+
+// Convert() normalises s to the lower half of [1,n-1].
+func TestConvert(t *testing.T) {
+	cp := p256.Params
+	ec := New()
+	ec.Curve = cp
+	ec.raw = true
+	l := cp.ScalarLen()
+	in := make([]byte, 2 * l)
+	big.NewInt(1).FillBytes(in[:l])
+	new(big.Int).Sub(cp.N, big.NewInt(2)).FillBytes(in[l:])
+	out := new(bytes.Buffer)
+	err := ec.Convert(bytes.NewReader(in), out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([]byte, 2 * l)
+	big.NewInt(1).FillBytes(want[:l])
+	big.NewInt(2).FillBytes(want[l:])
+	if bytes.Equal(out.Bytes(), want) == false {
+		t.Errorf("got %x, want %x", out.Bytes(), want)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// This file maps ECDSA keys to and from JSON Web Keys, as specified in
+// RFC 7518, section 6.2, and RFC 8812, section 3.1 (secp256k1).
+
+package ecdsa
+
+import (
+	"errors"
+	"godot/ecdsa/curve"
+	"godot/ecdsa/p256"
+	"godot/ecdsa/p384"
+	"godot/ecdsa/p521"
+	"godot/ecdsa/sec1"
+	"godot/ecdsa/secp256k1"
+	"godot/jwk"
+)
+
+// jwkCurve() returns the JWK name of a curve.
+func jwkCurve(cp *curve.Params) string {
+	switch cp {
+	case secp256k1.Params:
+		return "secp256k1"
+	case p256.Params:
+		return "P-256"
+	case p384.Params:
+		return "P-384"
+	case p521.Params:
+		return "P-521"
+	default:
+		return ""
+	}
+}
+
+// curveByJWK() looks up a curve by its JWK name.
+func curveByJWK(name string) (*curve.Params, error) {
+	for _, cp := range curves {
+		if jwkCurve(cp) == name {
+			return cp, nil
+		}
+	}
+
+	return nil, errors.New("unsupported curve")
+}
+
+// toJWK() converts the private key into a JWK. If pub is true, only
+// the public members are set. Coordinates and scalars are encoded in
+// full length, as required by RFC 7518.
+func (ec *ecdsa) toJWK(pub bool) (*jwk.Key, error) {
+	var j = new(jwk.Key)
+
+	cp := ec.Curve
+	x, y, err := ec.Private.GetPoint(cp)
+	if err != nil {
+		return nil, err
+	}
+	j.Kty = "EC"
+	j.Crv = jwkCurve(cp)
+	j.X, err = jwk.EncodeInt(x, cp.ByteLen())
+	if err != nil {
+		return nil, err
+	}
+	j.Y, err = jwk.EncodeInt(y, cp.ByteLen())
+	if err != nil {
+		return nil, err
+	}
+	if pub {
+		return j, nil
+	}
+	d, err := ec.Private.GetGenerator()
+	if err != nil {
+		return nil, err
+	}
+	j.D, err = jwk.EncodeInt(d, cp.ScalarLen())
+	if err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// pointFromJWK() returns the curve and public point of a JWK.
+func pointFromJWK(j *jwk.Key) (*curve.Params, *sec1.PublicKey, error) {
+	if j.Kty != "EC" {
+		return nil, nil, errors.New("not an EC key")
+	}
+	cp, err := curveByJWK(j.Crv)
+	if err != nil {
+		return nil, nil, err
+	}
+	x, err := jwk.DecodeInt(j.X, cp.ByteLen())
+	if err != nil {
+		return nil, nil, err
+	}
+	y, err := jwk.DecodeInt(j.Y, cp.ByteLen())
+	if err != nil {
+		return nil, nil, err
+	}
+	k, err := new(sec1.PublicKey).SetPoint(x, y, cp.ByteLen())
+	if err != nil {
+		return nil, nil, err
+	}
+	k.SetCurve(&cp.OID)
+
+	return cp, k, nil
+}
+
+// privFromJWK() converts a JWK into a private key, returning its
+// curve.
+func privFromJWK(j *jwk.Key) (*curve.Params, *sec1.PrivateKey, error) {
+	cp, pub, err := pointFromJWK(j)
+	if err != nil {
+		return nil, nil, err
+	}
+	if j.IsPrivate() == false {
+		return nil, nil, errors.New("not a private key")
+	}
+	d, err := jwk.DecodeInt(j.D, cp.ScalarLen())
+	if err != nil {
+		return nil, nil, err
+	}
+	x, y, err := pub.GetPoint(cp)
+	if err != nil {
+		return nil, nil, err
+	}
+	k := new(sec1.PrivateKey)
+	err = k.SetCurve(&cp.OID)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = k.SetPoint(x, y, cp.ByteLen())
+	if err != nil {
+		return nil, nil, err
+	}
+	err = k.SetGenerator(d, cp.ScalarLen())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cp, k, nil
+}
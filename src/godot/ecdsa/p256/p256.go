@@ -0,0 +1,68 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// The p256 module defines the parameters of NIST P-256 (secp256r1, prime256v1)
+// as given in FIPS 186-4, appendix D.1.2.
+
+package p256
+
+import (
+	"encoding/asn1"
+	"godot/ecdsa/curve"
+	"math/big"
+)
+
+var OID asn1.ObjectIdentifier = []int{1, 2, 840, 10045, 3, 1, 7}
+
+// The order of the prime field over which P-256 is defined:
+// 2^256 - 2^224 + 2^192 + 2^96 - 1.
+var fieldOrder = new(big.Int).SetBytes([]byte {
+	0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x01,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+})
+
+// The order of the base point G.
+var baseOrder = new(big.Int).SetBytes([]byte {
+	0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0xbc, 0xe6, 0xfa, 0xad, 0xa7, 0x17, 0x9e, 0x84,
+	0xf3, 0xb9, 0xca, 0xc2, 0xfc, 0x63, 0x25, 0x51,
+})
+
+// The coefficient b; a = -3.
+var coeffB = new(big.Int).SetBytes([]byte {
+	0x5a, 0xc6, 0x35, 0xd8, 0xaa, 0x3a, 0x93, 0xe7,
+	0xb3, 0xeb, 0xbd, 0x55, 0x76, 0x98, 0x86, 0xbc,
+	0x65, 0x1d, 0x06, 0xb0, 0xcc, 0x53, 0xb0, 0xf6,
+	0x3b, 0xce, 0x3c, 0x3e, 0x27, 0xd2, 0x60, 0x4b,
+})
+
+// The x-coordinate of the base point G.
+var baseX = new(big.Int).SetBytes([]byte {
+	0x6b, 0x17, 0xd1, 0xf2, 0xe1, 0x2c, 0x42, 0x47,
+	0xf8, 0xbc, 0xe6, 0xe5, 0x63, 0xa4, 0x40, 0xf2,
+	0x77, 0x03, 0x7d, 0x81, 0x2d, 0xeb, 0x33, 0xa0,
+	0xf4, 0xa1, 0x39, 0x45, 0xd8, 0x98, 0xc2, 0x96,
+})
+
+// The y-coordinate of the base point G.
+var baseY = new(big.Int).SetBytes([]byte {
+	0x4f, 0xe3, 0x42, 0xe2, 0xfe, 0x1a, 0x7f, 0x9b,
+	0x8e, 0xe7, 0xeb, 0x4a, 0x7c, 0x0f, 0x9e, 0x16,
+	0x2b, 0xce, 0x33, 0x57, 0x6b, 0x31, 0x5e, 0xce,
+	0xcb, 0xb6, 0x40, 0x68, 0x37, 0xbf, 0x51, 0xf5,
+})
+
+var Params = &curve.Params {
+	Name: "p256",
+	OID:  OID,
+	P:    fieldOrder,
+	N:    baseOrder,
+	A:    big.NewInt(-3),
+	B:    coeffB,
+	Gx:   baseX,
+	Gy:   baseY,
+}
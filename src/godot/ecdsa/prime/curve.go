@@ -8,6 +8,7 @@
 package prime
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"math/big"
 )
@@ -28,13 +29,30 @@ func (c *Curve) IsInf(p *Point) bool {
 	return p.x == nil && p.y == nil
 }
 
-func (c *Curve) Define(f *Field, a, b int) *Curve {
+// Define() sets the curve's field and coefficients. The coefficients
+// are reduced modulo the order of the field, so that curves with a
+// negative a (such as a = -3) can be defined.
+func (c *Curve) Define(f *Field, a, b *big.Int) *Curve {
 	c.f = f
-	c.a = f.Int64(int64(a))
-	c.b = f.Int64(int64(b))
+	c.a = f.Element(new(big.Int).Mod(a, f.n))
+	c.b = f.Element(new(big.Int).Mod(b, f.n))
 	return c
 }
 
+// CheckedDefine() is like Define(), but fails if the curve is
+// singular, i.e. if 4*a^3 + 27*b^2 = 0.
+func (c *Curve) CheckedDefine(f *Field, a, b *big.Int) (*Curve, error) {
+	c.Define(f, a, b)
+	l := f.NewElement().Exp(c.a, f.Int64(3))
+	l.Mul(l, f.Int64(4))
+	r := f.NewElement().Mul(c.b, c.b)
+	r.Mul(r, f.Int64(27))
+	if l.Add(l, r).GetValue().Sign() == 0 {
+		return nil, ErrSingular
+	}
+	return c, nil
+}
+
 func (c *Curve) NewPoint() *Point {
 	return new(Point).SetCurve(c)
 }
@@ -44,17 +62,59 @@ func (p *Point) SetCurve(c *Curve) *Point {
 	return p
 }
 
-func (p *Point) Set(x, y *Element) *Point {
-	c := p.c
+// rhs() calculates the right-hand side of the curve equation.
+func (c *Curve) rhs(x *Element) *Element {
 	f := c.f
-	// calculate both sides of the curve equation
-	l := f.NewElement().Exp(y, f.Int64(2)) // y^2
 	r := f.NewElement().Exp(x, f.Int64(3)) // x^3
 	r.Add(r, f.NewElement().Mul(c.a, x))   // x^3 + a*x
 	r.Add(r, c.b)                          // x^3 + a*x + b
-	if l.Cmp(r) != 0 {
-		panic("point not on curve")
+	return r
+}
+
+// IsOnCurve() checks whether (x,y) satisfies the curve equation.
+func (c *Curve) IsOnCurve(x, y *Element) bool {
+	f := c.f
+	l := f.NewElement().Exp(y, f.Int64(2)) // y^2
+	return l.Cmp(c.rhs(x)) == 0
+}
+
+func (p *Point) Set(x, y *Element) *Point {
+	_, err := p.CheckedSet(x, y)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (p *Point) CheckedSet(x, y *Element) (*Point, error) {
+	if p.c.IsOnCurve(x, y) == false {
+		return nil, ErrOffCurve
 	}
+	return p.set(x, y), nil
+}
+
+// SetX() sets p to the point whose x-coordinate is x and whose
+// y-coordinate is odd if odd is 1 and even if odd is 0. If there is no
+// such point, SetX() returns nil and p is left untouched.
+func (p *Point) SetX(x *Element, odd uint) *Point {
+	c := p.c
+	f := c.f
+	y := f.NewElement().Sqrt(c.rhs(x))
+	if y == nil {
+		return nil
+	}
+	if y.v.Bit(0) != odd {
+		y = f.NewElement().Neg(y)
+		if y.v.Bit(0) != odd {
+			return nil // y = 0
+		}
+	}
+	return p.set(x, y)
+}
+
+// set() sets the coordinates of a point without checking whether it
+// lies on the curve. It is used for the results of our own arithmetic.
+func (p *Point) set(x, y *Element) *Point {
 	p.x = x
 	p.y = y
 
@@ -81,12 +141,12 @@ func (p *Point) Neg(t *Point) *Point {
 	if c.IsInf(t) {
 		return p.SetInf() // -inf = inf
 	} else {
-		return p.Set(t.x, f.NewElement().Neg(t.y))
+		return p.set(t.x, f.NewElement().Neg(t.y))
 	}
 }
 
 func (p *Point) Equal(t *Point) bool {
-	if p == t {
+	if p == t || (p.c.IsInf(p) && t.c.IsInf(t)) {
 		return true
 	} else if p.x != nil && t.x != nil {
 		return p.x.Cmp(t.x) == 0 && p.y.Cmp(t.y) == 0
@@ -107,67 +167,66 @@ func (p *Point) String() string {
 // The three algorithms below (point doubling, addition and
 // multiplication) follow the definitions given in Guide to Elliptic
 // Curve Cryptogaphy by Hankerson, Menezes & Vanstone, first edition.
+// The actual arithmetic is performed in Jacobian coordinates (see
+// jacobian.go).
 
-// Section 3.1.2
+// Section 3.2.2
 func (p *Point) Double(t *Point) *Point {
-	c := t.c
-	if t.Equal(c.NewPoint().Neg(t)) {
-		return p.SetInf()
-	}
-
-	f := c.f
-	x := f.NewElement()
-	y := f.NewElement()
-
-	y.Exp(t.x, f.Int64(2))
-	y.Mul(y, f.Int64(3))
-	y.Add(y, c.a)
-	y.Div(y, f.NewElement().Mul(f.Int64(2), t.y))
-	x.Exp(y, f.Int64(2))
-	x.Sub(x, f.NewElement().Mul(f.Int64(2), t.x))
-	y.Mul(y, f.NewElement().Sub(t.x, x))
-	y.Sub(y, t.y)
-
-	return p.Set(x, y)
+	return new(jacobian).double(t.toJacobian()).toAffine(p)
 }
 
-// Section 3.1.2
+// Section 3.2.2
 func (p *Point) Add(t, u *Point) *Point {
-	c := t.c
-	if t.Equal(u) || t.Equal(c.NewPoint().Neg(u)) {
-		return p.SetInf()
-	} else if c.IsInf(u) {
-		return p.Set(t.x, t.y)
-	} else if c.IsInf(t) {
-		return p.Set(u.x, u.y)
-	}
-
-	f := c.f
-	x := f.NewElement()
-	y := f.NewElement()
-	y.Sub(u.y, t.y)
-	y.Div(y, f.NewElement().Sub(u.x, t.x))
-	x.Exp(y, f.Int64(2))
-	x.Sub(x, t.x)
-	x.Sub(x, u.x)
-	y.Mul(y, f.NewElement().Sub(t.x, x))
-	y.Sub(y, t.y)
-
-	return p.Set(x, y)
+	return new(jacobian).add(t.toJacobian(), u.toJacobian()).toAffine(p)
 }
 
-// Algorithm 3.26
+// Algorithm 3.26. Only one field inversion is performed, when the
+// result is converted back to affine coordinates. Mul() branches on
+// the bits of k, and must therefore only be used with public scalars;
+// see MulSecret().
 func (p *Point) Mul(t *Point, k *big.Int) *Point {
-	c := t.c
-	u := c.NewPoint().Set(t.x, t.y)
-	p.SetInf()
+	u := t.toJacobian()
+	q := t.c.jacobianInf()
 
 	for i := 0; i < k.BitLen(); i++ {
 		if k.Bit(i) == 1 {
-			p.Add(p, u)
+			q.add(q, u)
 		}
-		u.Double(u)
+		u.double(u)
 	}
 
-	return p
+	return q.toAffine(p)
+}
+
+// MulSecret() computes k*t, where k is a secret scalar in [0,n) and n
+// is the order of t, using the Montgomery ladder. Every bit of k
+// results in the same sequence of operations: one addition, one
+// doubling, and two conditional swaps. The bit length of k is masked
+// by adding n (or 2n) to it beforehand. Note that Go's big.Int
+// arithmetic is not itself constant-time, so this is a best effort.
+func (p *Point) MulSecret(t *Point, k, n *big.Int) *Point {
+	// k + n and k + 2n are both congruent to k modulo n. Pick the
+	// one whose bit length is that of n plus one. k + 2n may need
+	// two more bits than n, and both are encoded.
+	nBits := n.BitLen()
+	l := (nBits + 2 + 7) / 8
+	k1 := new(big.Int).Add(k, n)
+	k2 := new(big.Int).Add(k1, n)
+	b1 := k1.FillBytes(make([]byte, l))
+	b2 := k2.FillBytes(make([]byte, l))
+	subtle.ConstantTimeCopy(1 - int(k1.Bit(nBits)), b1, b2)
+	kk := new(big.Int).SetBytes(b1)
+
+	// The top bit of kk is set, so the ladder starts at (t,2t).
+	r0 := t.toJacobian()
+	r1 := new(jacobian).double(r0)
+	for i := nBits - 1; i >= 0; i-- {
+		b := kk.Bit(i)
+		r0.cswap(r1, b)
+		r1.add(r0, r1)
+		r0.double(r0)
+		r0.cswap(r1, b)
+	}
+
+	return r0.toAffine(p)
 }
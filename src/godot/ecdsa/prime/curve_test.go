@@ -0,0 +1,73 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package prime
+
+import (
+	"math/big"
+	"testing"
+)
+
+// The curves are given here rather than imported, as the packages that
+// define them import prime.
+var curves = []struct {
+	name            string
+	p, a, b, gx, gy string
+	n               string
+}{
+	{
+		"secp256k1",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC2F",
+		"0",
+		"7",
+		"79BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798",
+		"483ADA7726A3C4655DA4FBFC0E1108A8FD17B448A68554199C47D08FFB10D4B8",
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141",
+	},
+	{
+		"P-256",
+		"FFFFFFFF00000001000000000000000000000000FFFFFFFFFFFFFFFFFFFFFFFF",
+		"-3",
+		"5AC635D8AA3A93E7B3EBBD55769886BC651D06B0CC53B0F63BCE3C3E27D2604B",
+		"6B17D1F2E12C4247F8BCE6E563A440F277037D812DEB33A0F4A13945D898C296",
+		"4FE342E2FE1A7F9B8EE7EB4A7C0F9E162BCE33576B315ECECBB6406837BF51F5",
+		"FFFFFFFF00000000FFFFFFFFFFFFFFFFBCE6FAADA7179E84F3B9CAC2FC632551",
+	},
+}
+
+func hexInt(t *testing.T, s string) *big.Int {
+	x, ok := new(big.Int).SetString(s, 16)
+	if ok == false {
+		t.Fatalf("bad hex %s", s)
+	}
+
+	return x
+}
+
+// MulSecret() must agree with Mul() for scalars near 0 and n-1, and
+// for scalars of n or more, which are congruent to smaller ones.
+func TestMulSecret(t *testing.T) {
+	for _, v := range curves {
+		f := new(Field).SetOrder(hexInt(t, v.p))
+		c := new(Curve).Define(f, hexInt(t, v.a), hexInt(t, v.b))
+		g := c.NewPoint().Set(f.Element(hexInt(t, v.gx)),
+		    f.Element(hexInt(t, v.gy)))
+		n := hexInt(t, v.n)
+
+		var ks []*big.Int
+		for i := int64(0); i < 3; i++ {
+			ks = append(ks, big.NewInt(i))
+			ks = append(ks, new(big.Int).Sub(n, big.NewInt(i + 1)))
+			ks = append(ks, new(big.Int).Add(n, big.NewInt(i)))
+		}
+		for _, k := range ks {
+			p := c.NewPoint().MulSecret(g, k, n)
+			q := c.NewPoint().Mul(g, new(big.Int).Mod(k, n))
+			if p.Equal(q) == false {
+				t.Errorf("%s: k = %x: got %v, want %v", v.name,
+				    k, p, q)
+			}
+		}
+	}
+}
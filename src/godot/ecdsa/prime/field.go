@@ -3,13 +3,25 @@
 // that can be found in the LICENSE file.
 //
 // field.go implements modulo arithmetic over prime fields.
+//
+// Functions that panic on bad input have a Checked counterpart that
+// returns one of the errors below instead.
 
 package prime
 
 import (
+	"errors"
 	"math/big"
 )
 
+var (
+	ErrOrder    = errors.New("prime: invalid field order")
+	ErrRange    = errors.New("prime: integer out of field range")
+	ErrInverse  = errors.New("prime: element not invertible")
+	ErrOffCurve = errors.New("prime: point not on curve")
+	ErrSingular = errors.New("prime: singular curve")
+)
+
 type Field struct {
 	n *big.Int // must be a prime
 }
@@ -24,6 +36,16 @@ func (f *Field) SetOrder(n *big.Int) *Field {
 	return f
 }
 
+// CheckedSetOrder() is like SetOrder(), but fails if n is obviously not
+// an odd prime. n is not tested for primality; should it be composite,
+// this is detected when an element turns out not to be invertible.
+func (f *Field) CheckedSetOrder(n *big.Int) (*Field, error) {
+	if n.Cmp(big.NewInt(3)) == -1 || n.Bit(0) == 0 {
+		return nil, ErrOrder
+	}
+	return f.SetOrder(n), nil
+}
+
 func (f *Field) Int64(v int64) *Element {
 	return new(Element).SetField(f).SetValue(big.NewInt(v))
 }
@@ -32,6 +54,11 @@ func (f *Field) Element(v *big.Int) *Element {
 	return new(Element).SetField(f).SetValue(v)
 }
 
+// CheckedElement() is like Element(), but fails if v is not in [0,n).
+func (f *Field) CheckedElement(v *big.Int) (*Element, error) {
+	return new(Element).SetField(f).CheckedSetValue(v)
+}
+
 func (f *Field) NewElement() *Element {
 	return f.Int64(0)
 }
@@ -50,12 +77,20 @@ func (e *Element) SetField(f *Field) *Element {
 }
 
 func (e *Element) SetValue(v *big.Int) *Element {
+	_, err := e.CheckedSetValue(v)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+func (e *Element) CheckedSetValue(v *big.Int) (*Element, error) {
 	if v.Cmp(big.NewInt(0)) == -1 || v.Cmp(e.f.n) != -1 {
-		panic("integer out of field range")
+		return nil, ErrRange
 	}
 	e.v = v
 
-	return e
+	return e, nil
 }
 
 func (e *Element) Mod(v *big.Int) *Element {
@@ -63,12 +98,22 @@ func (e *Element) Mod(v *big.Int) *Element {
 	return e
 }
 
+// Since x and y are both in [0,n), Add() and Sub() only need a
+// conditional subtraction (or addition) of n instead of a division.
 func (e *Element) Add(x, y *Element) *Element {
-	return e.Mod(e.v.Add(x.v, y.v))
+	e.v.Add(x.v, y.v)
+	if e.v.Cmp(e.f.n) != -1 {
+		e.v.Sub(e.v, e.f.n)
+	}
+	return e
 }
 
 func (e *Element) Sub(x, y *Element) *Element {
-	return e.Mod(e.v.Sub(x.v, y.v))
+	e.v.Sub(x.v, y.v)
+	if e.v.Sign() == -1 {
+		e.v.Add(e.v, e.f.n)
+	}
+	return e
 }
 
 func (e *Element) Mul(x, y *Element) *Element {
@@ -79,6 +124,14 @@ func (e *Element) Div(x, y *Element) *Element {
 	return e.Mul(x, new(Element).Inv(y))
 }
 
+func (e *Element) CheckedDiv(x, y *Element) (*Element, error) {
+	yInv, err := new(Element).CheckedInv(y)
+	if err != nil {
+		return nil, err
+	}
+	return e.Mul(x, yInv), nil
+}
+
 func (e *Element) Exp(x, y *Element) *Element {
 	e.v.Exp(x.v, y.v, e.f.n)
 	return e
@@ -89,6 +142,19 @@ func (e *Element) Neg(x *Element) *Element {
 	return f.NewElement().Sub(f.Int64(0), x)
 }
 
+// Sqrt() sets e to a square root of x. If x is not a square, Sqrt()
+// returns nil and e is left untouched.
+func (e *Element) Sqrt(x *Element) *Element {
+	v := new(big.Int).ModSqrt(x.v, x.f.n)
+	if v == nil {
+		return nil
+	}
+	e.f = x.f
+	e.v = v
+
+	return e
+}
+
 func (e *Element) Cmp(x *Element) int {
 	return e.v.Cmp(x.v)
 }
@@ -96,17 +162,27 @@ func (e *Element) Cmp(x *Element) int {
 // Inv() uses Go's GCD() to compute the inverse x of an element a on
 // a prime field of order n, i.e x such that (a*x)modn = 1.
 func (e *Element) Inv(a *Element) *Element {
+	_, err := e.CheckedInv(a)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// CheckedInv() is like Inv(), but fails if a is not invertible, which
+// happens if a is zero or the order of the field is not a prime.
+func (e *Element) CheckedInv(a *Element) (*Element, error) {
 	var d = new(big.Int)
 	var x = new(big.Int)
 	var f = a.f
 
 	// d must be 1, since a.v < f.n and f.n is prime.
 	if d.GCD(x, nil, a.v, f.n).Cmp(big.NewInt(1)) != 0 {
-		panic("bogus parameters in element inversion")
+		return nil, ErrInverse
 	}
 
 	e.f = f
 	e.v = x.Mod(x, f.n)
 
-	return e
+	return e, nil
 }
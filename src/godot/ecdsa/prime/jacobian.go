@@ -0,0 +1,174 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// jacobian.go implements elliptic curve arithmetic in Jacobian
+// projective coordinates, where a point (X,Y,Z) corresponds to the
+// affine point (X/Z^2,Y/Z^3). Points are only converted back to affine
+// coordinates at the end of a computation, saving one field inversion
+// per addition or doubling.
+
+package prime
+
+import (
+	"crypto/subtle"
+	"math/big"
+)
+
+// A point with Z = 0 is considered a point at infinity.
+type jacobian struct {
+	c     *Curve    // associated curve
+	x,y,z *Element  // projective coordinates
+}
+
+// jacobianInf() returns the point at infinity, (1,1,0).
+func (c *Curve) jacobianInf() *jacobian {
+	f := c.f
+	return &jacobian{c, f.Int64(1), f.Int64(1), f.Int64(0)}
+}
+
+// toJacobian() maps an affine point p to (x,y,1).
+func (p *Point) toJacobian() *jacobian {
+	c := p.c
+	f := c.f
+	if c.IsInf(p) {
+		return c.jacobianInf()
+	}
+	x := f.Element(new(big.Int).Set(p.x.v))
+	y := f.Element(new(big.Int).Set(p.y.v))
+	return &jacobian{c, x, y, f.Int64(1)}
+}
+
+func (j *jacobian) isInf() bool {
+	return j.z.v.Sign() == 0
+}
+
+func (j *jacobian) set(t *jacobian) *jacobian {
+	j.c, j.x, j.y, j.z = t.c, t.x, t.y, t.z
+	return j
+}
+
+// toAffine() maps (X,Y,Z) to (X/Z^2,Y/Z^3), with a single inversion.
+func (j *jacobian) toAffine(p *Point) *Point {
+	c := j.c
+	f := c.f
+	p.c = c
+	if j.isInf() {
+		return p.SetInf()
+	}
+	zInv := f.NewElement().Inv(j.z)
+	zInv2 := f.NewElement().Mul(zInv, zInv)
+	zInv3 := f.NewElement().Mul(zInv2, zInv)
+
+	return p.set(f.NewElement().Mul(j.x, zInv2),
+	    f.NewElement().Mul(j.y, zInv3))
+}
+
+// Section 3.2.2, generalised to an arbitrary a:
+// S = 4*X*Y^2, M = 3*X^2 + a*Z^4, X' = M^2 - 2*S,
+// Y' = M*(S - X') - 8*Y^4, Z' = 2*Y*Z.
+func (j *jacobian) double(t *jacobian) *jacobian {
+	c := t.c
+	f := c.f
+	if t.isInf() || t.y.v.Sign() == 0 {
+		return j.set(c.jacobianInf())
+	}
+
+	yy := f.NewElement().Mul(t.y, t.y)
+	s := f.NewElement().Mul(t.x, yy)
+	s.Add(s, s)
+	s.Add(s, s)                             // 4*X*Y^2
+	zz := f.NewElement().Mul(t.z, t.z)
+	m := f.NewElement().Mul(zz, zz)
+	m.Mul(m, c.a)                           // a*Z^4
+	xx := f.NewElement().Mul(t.x, t.x)
+	m.Add(m, xx)
+	m.Add(m, xx)
+	m.Add(m, xx)                            // 3*X^2 + a*Z^4
+
+	x := f.NewElement().Mul(m, m)
+	x.Sub(x, s)
+	x.Sub(x, s)                             // M^2 - 2*S
+	y := f.NewElement().Sub(s, x)
+	y.Mul(y, m)
+	yyyy := f.NewElement().Mul(yy, yy)
+	yyyy.Add(yyyy, yyyy)
+	yyyy.Add(yyyy, yyyy)
+	yyyy.Add(yyyy, yyyy)                    // 8*Y^4
+	y.Sub(y, yyyy)
+	z := f.NewElement().Mul(t.y, t.z)
+	z.Add(z, z)                             // 2*Y*Z
+
+	return j.set(&jacobian{c, x, y, z})
+}
+
+// Section 3.2.2 (full addition):
+// U1 = X1*Z2^2, U2 = X2*Z1^2, S1 = Y1*Z2^3, S2 = Y2*Z1^3,
+// H = U2 - U1, R = S2 - S1, X3 = R^2 - H^3 - 2*U1*H^2,
+// Y3 = R*(U1*H^2 - X3) - S1*H^3, Z3 = H*Z1*Z2.
+func (j *jacobian) add(t, u *jacobian) *jacobian {
+	c := t.c
+	f := c.f
+	if t.isInf() {
+		return j.set(u)
+	} else if u.isInf() {
+		return j.set(t)
+	}
+
+	z1z1 := f.NewElement().Mul(t.z, t.z)
+	z2z2 := f.NewElement().Mul(u.z, u.z)
+	u1 := f.NewElement().Mul(t.x, z2z2)
+	u2 := f.NewElement().Mul(u.x, z1z1)
+	s1 := f.NewElement().Mul(t.y, z2z2.Mul(z2z2, u.z))
+	s2 := f.NewElement().Mul(u.y, z1z1.Mul(z1z1, t.z))
+	if u1.Cmp(u2) == 0 {
+		if s1.Cmp(s2) != 0 {
+			return j.set(c.jacobianInf()) // t = -u
+		}
+		return j.double(t)
+	}
+
+	h := f.NewElement().Sub(u2, u1)
+	r := f.NewElement().Sub(s2, s1)
+	hh := f.NewElement().Mul(h, h)
+	hhh := f.NewElement().Mul(hh, h)
+	v := f.NewElement().Mul(u1, hh)
+
+	x := f.NewElement().Mul(r, r)
+	x.Sub(x, hhh)
+	x.Sub(x, v)
+	x.Sub(x, v)
+	y := f.NewElement().Sub(v, x)
+	y.Mul(y, r)
+	y.Sub(y, f.NewElement().Mul(s1, hhh))
+	z := f.NewElement().Mul(t.z, u.z)
+	z.Mul(z, h)
+
+	return j.set(&jacobian{c, x, y, z})
+}
+
+// cswap() swaps the coordinates of j and t if swap is 1, and leaves
+// them untouched if swap is 0. The same operations are performed in
+// both cases. Fresh elements are always allocated, so that elements
+// shared with other points are never modified.
+func (j *jacobian) cswap(t *jacobian, swap uint) {
+	l := (j.c.f.n.BitLen() + 7) / 8
+	mask := byte(subtle.ConstantTimeByteEq(uint8(swap), 1) * 0xff)
+	a := []*Element{j.x, j.y, j.z}
+	b := []*Element{t.x, t.y, t.z}
+
+	for i := range a {
+		x := a[i].v.FillBytes(make([]byte, l))
+		y := b[i].v.FillBytes(make([]byte, l))
+		for k := range x {
+			d := mask & (x[k] ^ y[k])
+			x[k] ^= d
+			y[k] ^= d
+		}
+		a[i] = a[i].f.Element(new(big.Int).SetBytes(x))
+		b[i] = b[i].f.Element(new(big.Int).SetBytes(y))
+	}
+
+	j.x, j.y, j.z = a[0], a[1], a[2]
+	t.x, t.y, t.z = b[0], b[1], b[2]
+}
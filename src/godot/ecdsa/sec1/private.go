@@ -5,9 +5,13 @@
 package sec1
 
 import (
+	"bytes"
 	"encoding/asn1"
 	"encoding/pem"
 	"errors"
+	"godot/ecdsa/curve"
+	"godot/pkcs8"
+	"godot/util"
 	"math/big"
 	"io"
 	"io/ioutil"
@@ -19,13 +23,24 @@ var (
 	ErrPemDecode = errors.New("sec1: pem decode error")
 	ErrBadPem    = errors.New("sec1: invalid pem")
 	ErrBadPoint  = errors.New("sec1: invalid point")
+	ErrBadSig    = errors.New("sec1: non-canonical signature")
+
+	// returned by Validate()
+	ErrRange     = errors.New("sec1: coordinate out of range")
+	ErrOffCurve  = errors.New("sec1: point not on curve")
+	ErrOrder     = errors.New("sec1: point of wrong order")
+	ErrScalar    = errors.New("sec1: private key out of range")
+	ErrMismatch  = errors.New("sec1: private and public keys do not match")
 )
 
+// As per RFC 5915, section 3. The public key may be absent, and so
+// may the parameters when the key is wrapped in PKCS#8, which then
+// carries them instead.
 type PrivateKey struct {
 	Version    *big.Int
 	PrivateKey  []byte
-	Parameters  asn1.RawValue
-	PublicKey   asn1.RawValue
+	Parameters  asn1.RawValue `asn1:"optional,tag:0"`
+	PublicKey   asn1.RawValue `asn1:"optional,tag:1"`
 }
 
 type Signature struct {
@@ -33,10 +48,12 @@ type Signature struct {
 	S *big.Int
 }
 
-// SetGenerator() sets the generator d of a private key.
-func (ec *PrivateKey) SetGenerator(d *big.Int) *PrivateKey {
-	ec.PrivateKey = d.Bytes()
-	return ec
+// SetGenerator() sets the generator d of a private key. As per RFC
+// 5915, d is encoded in l bytes, the length of the curve's order.
+func (ec *PrivateKey) SetGenerator(d *big.Int, l int) error {
+	var err error
+	ec.PrivateKey, err = pad(d, l)
+	return err
 }
 
 // GetGenerator() retrieves the generator d of a private key.
@@ -78,9 +95,10 @@ func (ec *PrivateKey) GetCurveID() (*asn1.ObjectIdentifier, error) {
 	return oid, nil
 }
 
-// SetPoint() sets the public point q of a private key.
-func (ec *PrivateKey) SetPoint(x, y *big.Int) error {
-	pub, err := new(PublicKey).SetPoint(x, y)
+// SetPoint() sets the public point q of a private key. Each coordinate
+// is encoded in l bytes, the length of a field element.
+func (ec *PrivateKey) SetPoint(x, y *big.Int, l int) error {
+	pub, err := new(PublicKey).SetPoint(x, y, l)
 	if err != nil {
 		return err
 	}
@@ -95,8 +113,31 @@ func (ec *PrivateKey) SetPoint(x, y *big.Int) error {
 	return err
 }
 
-// GetPoint() retrieves the public point q of a private key.
-func (ec *PrivateKey) GetPoint() (*big.Int, *big.Int, error) {
+// hasPoint() tells whether the optional public point of a private key
+// is present.
+func (ec *PrivateKey) hasPoint() bool {
+	return ec.PublicKey.FullBytes != nil || ec.PublicKey.Bytes != nil
+}
+
+// GetPoint() retrieves the public point q of a private key on the
+// curve cp. As per RFC 5915, section 3, the point is optional; if it
+// is absent, q is derived as d*G.
+func (ec *PrivateKey) GetPoint(cp *curve.Params) (*big.Int, *big.Int,
+    error) {
+	if ec.hasPoint() == false {
+		d, err := ec.GetGenerator()
+		if err != nil {
+			return nil, nil, err
+		}
+		if d.Sign() != 1 || d.Cmp(cp.N) != -1 {
+			return nil, nil, ErrScalar
+		}
+		q, err := cp.Public(d)
+		if err != nil {
+			return nil, nil, err
+		}
+		return q.GetX(), q.GetY(), nil
+	}
 	v := &ec.PublicKey
 	if v.Class != asn1.ClassContextSpecific ||
 	   v.IsCompound != true ||
@@ -110,7 +151,40 @@ func (ec *PrivateKey) GetPoint() (*big.Int, *big.Int, error) {
 		return nil, nil, err
 	}
 
-	return new(PublicKey).SetBytes(b.Bytes).GetPoint()
+	return new(PublicKey).SetBytes(b.Bytes).GetPoint(cp)
+}
+
+// Validate() checks a private key on the curve cp: d must be in
+// [1,n-1], and the public point, if present, must be valid as per
+// PublicKey.Validate() and equal to d*G.
+func (ec *PrivateKey) Validate(cp *curve.Params) error {
+	d, err := ec.GetGenerator()
+	if err != nil {
+		return err
+	}
+	if d.Sign() != 1 || d.Cmp(cp.N) != -1 {
+		return ErrScalar
+	}
+	if ec.hasPoint() == false {
+		return nil
+	}
+	x, y, err := ec.GetPoint(cp)
+	if err != nil {
+		return err
+	}
+	err = validatePoint(cp, x, y)
+	if err != nil {
+		return err
+	}
+	q, err := cp.Public(d)
+	if err != nil {
+		return err
+	}
+	if q.GetX().Cmp(x) != 0 || q.GetY().Cmp(y) != 0 {
+		return ErrMismatch
+	}
+
+	return nil
 }
 
 // Write() marshals a PEM-encoded private key.
@@ -128,27 +202,76 @@ func (ec *PrivateKey) Write(w io.Writer) error {
 	return pem.Encode(w, blob)
 }
 
-// Read() unmarshals a PEM-encoded private key.
+// WritePKCS8() marshals a PEM-encoded private key wrapped in PKCS#8.
+// As done by OpenSSL, the curve is only recorded in the PKCS#8
+// algorithm parameters.
+func (ec *PrivateKey) WritePKCS8(w io.Writer) error {
+	var alg pkcs8.AlgorithmIdentifier
+
+	k := *ec
+	k.Version = big.NewInt(1)
+	k.Parameters = asn1.RawValue{}
+	der, err := asn1.Marshal(k)
+	if err != nil {
+		return err
+	}
+	alg.Algorithm = ecPublicKey
+	alg.Parameters.FullBytes = ec.Parameters.Bytes // the curve's OID
+
+	return pkcs8.Write(alg, der, w)
+}
+
+// Read() unmarshals a PEM- or DER-encoded private key. The key may be
+// wrapped in PKCS#8.
 func (ec *PrivateKey) Read(r io.Reader) (*PrivateKey, error) {
 	body, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
-	blob, _ := pem.Decode(body)
-	if blob == nil {
+	der, t, ok := util.Unarmor(body)
+	if ok == false {
 		return nil, ErrPemDecode
 	}
-	if blob.Type != "EC PRIVATE KEY" || blob.Bytes == nil {
+	if t == "" {
+		t = pkcs8.TypeOf(der, "EC PRIVATE KEY")
+	}
+	var params []byte
+	switch {
+	case der == nil:
+		return nil, ErrBadPem
+	case t == "EC PRIVATE KEY":
+	case t == pkcs8.PemType:
+		k, err := pkcs8.Unmarshal(der)
+		if err != nil {
+			return nil, err
+		}
+		if ecPublicKey.Equal(k.Algorithm.Algorithm) == false {
+			return nil, ErrBadKey
+		}
+		der = k.PrivateKey
+		params = k.Algorithm.Parameters.FullBytes
+	default:
 		return nil, ErrBadPem
 	}
 
-	_, err = asn1.Unmarshal(blob.Bytes, ec)
+	_, err = asn1.Unmarshal(der, ec)
 	if err != nil {
 		return nil, err
 	}
 	if ec.Version == nil || ec.Version.Cmp(big.NewInt(1)) != 0 {
 		return nil, ErrBadKey
 	}
+	if ec.Parameters.Bytes == nil && params != nil {
+		oid := new(asn1.ObjectIdentifier)
+		_, err = asn1.Unmarshal(params, oid)
+		if err != nil {
+			return nil, err
+		}
+		err = ec.SetCurve(oid)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return ec, nil
 }
@@ -181,3 +304,30 @@ func (sig *Signature) Read(r io.Reader) (*Signature, error) {
 
 	return sig, nil
 }
+
+// ReadStrict() is like Read(), but only accepts signatures in strict
+// DER, as specified in BIP-66: no trailing data, no excess padding, and
+// positive integers only. This is checked by re-encoding the signature
+// and comparing the result with what was read.
+func (sig *Signature) ReadStrict(r io.Reader) (*Signature, error) {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	rest, err := asn1.Unmarshal(body, sig)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 || sig.R.Sign() != 1 || sig.S.Sign() != 1 {
+		return nil, ErrBadSig
+	}
+	der, err := asn1.Marshal(*sig)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(der, body) == false {
+		return nil, ErrBadSig
+	}
+
+	return sig, nil
+}
@@ -7,6 +7,8 @@ package sec1
 import (
 	"encoding/asn1"
 	"encoding/pem"
+	"godot/ecdsa/curve"
+	"godot/util"
 	"math/big"
 	"io"
 	"io/ioutil"
@@ -30,12 +32,29 @@ func (ec *PublicKey) SetBytes(p []byte) *PublicKey {
 	return ec
 }
 
-// SetPoint() sets the coordinates of the point q of a public key.
-func (ec *PublicKey) SetPoint(x, y *big.Int) (*PublicKey, error) {
-	qX, qY := x.Bytes(), y.Bytes()
-	if len(qX) != len(qY) {
+// pad() encodes v in exactly l bytes.
+func pad(v *big.Int, l int) ([]byte, error) {
+	b := v.Bytes()
+	if len(b) > l {
 		return nil, ErrBadPoint
 	}
+	p := make([]byte, l)
+	copy(p[l - len(b):], b)
+
+	return p, nil
+}
+
+// SetPoint() sets the coordinates of the point q of a public key. Each
+// coordinate is encoded in l bytes, the length of a field element.
+func (ec *PublicKey) SetPoint(x, y *big.Int, l int) (*PublicKey, error) {
+	qX, err := pad(x, l)
+	if err != nil {
+		return nil, err
+	}
+	qY, err := pad(y, l)
+	if err != nil {
+		return nil, err
+	}
 	p := make([]byte, 0, 1 + len(qX) + len(qY))
 	v := &ec.Point
 	v.Bytes = append(append(append(p, 0x04), qX...), qY...)
@@ -43,20 +62,75 @@ func (ec *PublicKey) SetPoint(x, y *big.Int) (*PublicKey, error) {
 	return ec, nil
 }
 
-// GetPoint() retrieves the coordinates of the point q of a public key.
-func (ec *PublicKey) GetPoint() (*big.Int, *big.Int, error) {
+// SetCompressed() is like SetPoint(), but only encodes the
+// x-coordinate of q and the parity of its y-coordinate (SEC1, section
+// 2.3.3).
+func (ec *PublicKey) SetCompressed(x, y *big.Int, l int) (*PublicKey,
+    error) {
+	qX, err := pad(x, l)
+	if err != nil {
+		return nil, err
+	}
+	p := make([]byte, 0, 1 + len(qX))
+	v := &ec.Point
+	v.Bytes = append(append(p, 0x02 | byte(y.Bit(0))), qX...)
+
+	return ec, nil
+}
+
+// GetPoint() retrieves the coordinates of the point q of a public key
+// on the curve cp. Both uncompressed and compressed points are
+// accepted.
+func (ec *PublicKey) GetPoint(cp *curve.Params) (*big.Int, *big.Int,
+    error) {
 	p := ec.Point.Bytes
 	if p == nil {
 		return nil, nil, ErrEmptyKey
 	}
-	if len(p) < 2 || (len(p) - 1) % 2 != 0 || p[0] != 0x04 {
+	l := cp.ByteLen()
+	switch {
+	case len(p) == 1 + 2 * l && p[0] == 0x04:
+		x := new(big.Int).SetBytes(p[1:1 + l])
+		y := new(big.Int).SetBytes(p[1 + l:])
+		return x, y, nil
+	case len(p) == 1 + l && (p[0] == 0x02 || p[0] == 0x03):
+		x := new(big.Int).SetBytes(p[1:])
+		y, err := cp.Decompress(x, uint(p[0] & 1))
+		if err != nil {
+			return nil, nil, ErrBadPoint
+		}
+		return x, y, nil
+	default:
 		return nil, nil, ErrBadKey
 	}
-	i := (len(p) - 1) / 2 + 1 // split p in half
-	x := new(big.Int).SetBytes(p[1:i])
-	y := new(big.Int).SetBytes(p[i:])
+}
+
+// validatePoint() performs the checks of SEC1, section 3.2.2.1. The
+// point at infinity cannot be encoded, so it need not be checked for.
+func validatePoint(cp *curve.Params, x, y *big.Int) error {
+	if x.Sign() == -1 || x.Cmp(cp.P) != -1 ||
+	   y.Sign() == -1 || y.Cmp(cp.P) != -1 {
+		return ErrRange
+	}
+	if cp.IsOnCurve(x, y) == false {
+		return ErrOffCurve
+	}
+	if cp.HasOrderN(x, y) == false {
+		return ErrOrder
+	}
+
+	return nil
+}
+
+// Validate() checks that the point q of a public key is a valid point
+// on the curve cp, as specified in SEC1, section 3.2.2.
+func (ec *PublicKey) Validate(cp *curve.Params) error {
+	x, y, err := ec.GetPoint(cp)
+	if err != nil {
+		return err
+	}
 
-	return x, y, nil
+	return validatePoint(cp, x, y)
 }
 
 // SetCurve() sets the curve ID of a public key.
@@ -85,21 +159,21 @@ func (ec *PublicKey) Write(w io.Writer) error {
 	return pem.Encode(w, blob)
 }
 
-// Read() unmarshals a PEM-encoded public key.
+// Read() unmarshals a PEM- or DER-encoded public key.
 func (ec *PublicKey) Read(r io.Reader) (*PublicKey, error) {
 	body, err := ioutil.ReadAll(r)
 	if err != nil {
 		return nil, err
 	}
-	blob, _ := pem.Decode(body)
-	if blob == nil {
+	der, t, ok := util.Unarmor(body)
+	if ok == false {
 		return nil, ErrPemDecode
 	}
-	if blob.Type != "PUBLIC KEY" || blob.Bytes == nil {
+	if (t != "" && t != "PUBLIC KEY") || der == nil {
 		return nil, ErrBadPem
 	}
 
-	_, err = asn1.Unmarshal(blob.Bytes, ec)
+	_, err = asn1.Unmarshal(der, ec)
 	if err != nil {
 		return nil, err
 	}
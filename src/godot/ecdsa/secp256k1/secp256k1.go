@@ -1,17 +1,15 @@
 // Copyright (c) 2016 Pedro Martelletto. All rights reserved.
 // Use of this source code is governed by a BSD-style license
 // that can be found in the LICENSE file.
+//
+// The secp256k1 module defines the parameters of secp256k1 as given
+// in SEC2, section 2.4.1.
 
 package secp256k1
 
 import (
-	"bytes"
 	"encoding/asn1"
-	"encoding/binary"
-	"errors"
-	"godot/ecdsa/prime"
-	"godot/rand"
-	"godot/sha256"
+	"godot/ecdsa/curve"
 	"math/big"
 )
 
@@ -50,177 +48,13 @@ var baseY = new(big.Int).SetBytes([]byte {
 	0x9c, 0x47, 0xd0, 0x8f, 0xfb, 0x10, 0xd4, 0xb8,
 })
 
-// getCurve() instantiates a secp256k1 curve's parameters (field,
-// curve, and base point).
-func getCurve() (*prime.Field, *prime.Curve, *prime.Point) {
-	f := new(prime.Field).SetOrder(fieldOrder)
-	c := new(prime.Curve).Define(f, 0, 7)
-	g := c.NewPoint().Set(f.Element(baseX), f.Element(baseY))
-	return f, c, g
-}
-
-// NewPair() returns a new secp256k1 key pair (q,d).
-func NewPair() (*prime.Point, *big.Int, error) {
-	var d *big.Int
-	var err error
-
-	for {
-		// rand.Int() returns an integer in [0,baseOrder).
-		// we want d in [1,baseOrder).
-		d, err = rand.Int(baseOrder)
-		if err != nil {
-			return nil, nil, err
-		} else if d.Cmp(big.NewInt(0)) == 1 {
-			break
-		}
-	}
-
-	_, c, g := getCurve()
-	q := c.NewPoint().Mul(g, d)
-
-	return q, d, nil
-}
-
-// Given a field order n, a point generator d, and a message m, nonce()
-// returns an integer k in the range [0,n) with a high probability of
-// being unique for a given combination (d, m), and which is difficult
-// to guess without knowledge of d.
-func nonce(n, d *big.Int, m []byte) (*big.Int, error) {
-	kLen := len(n.Bytes()) + 8
-	kBuf := new(bytes.Buffer)
-
-	for i := 0; i < kLen; i += sha256.Len {
-		r, err := rand.Bytes(sha256.Len)
-		if err != nil {
-			return nil, err
-		}
-		// p is the concatenation of i, d, m, and r.
-		p := bytes.NewBuffer(make([]byte, 0, 128))
-		err = binary.Write(p, binary.BigEndian, uint32(i))
-		if err != nil {
-			return nil, err
-		}
-		p.Write(d.Bytes())
-		p.Write(m)
-		p.Write(r)
-		h, err := sha256.DigestBytes(p.Bytes())
-		if err != nil {
-			return nil, err
-		}
-		kBuf.Write(h)
-	}
-
-	k := new(big.Int).SetBytes(kBuf.Bytes()[:kLen])
-
-	return k.Mod(k, n), nil
-}
-
-// randPoint() calculates a random point on the curve, returning the
-// point's x-coordinate r and generator k, where k is a nonce.
-func randPoint(h []byte, d *big.Int) (*big.Int, *big.Int, error) {
-	var k *big.Int
-	var n *big.Int = baseOrder
-	var err error
-
-	for {
-		k, err = nonce(n, d, h)
-		if err != nil {
-			return nil, nil, err
-		}
-		if k.Cmp(big.NewInt(0)) != 0 {
-			break
-		}
-	}
-
-	_, c, g := getCurve()
-	Gk := c.NewPoint().Mul(g, k)
-	r := new(big.Int).Mod(Gk.GetX(), n)
-
-	return k, r, nil
-}
-
-func doSign(h []byte, d *big.Int) (*big.Int, *big.Int, error) {
-	var k *big.Int
-	var r *big.Int
-	var err error
-
-	for {
-		k, r, err = randPoint(h, d)
-		if err != nil {
-			return nil, nil, err
-		}
-		if r.Cmp(big.NewInt(0)) != 0 {
-			break
-		}
-	}
-
-	n := baseOrder
-	e := new(big.Int).SetBytes(h)
-	e.Mod(e, n)
-
-	// Use the fact that n is prime to define an ephemeral field
-	// and perform modulo arithmetic.
-	f := new(prime.Field).SetOrder(n)
-	dF := f.Element(d)
-	eF := f.Element(e)
-	kF := f.Element(k)
-	rF := f.Element(r)
-
-	s := f.NewElement().Mul(dF, rF)
-	s.Add(s, eF)
-	s.Div(s, kF)
-
-	return r, s.GetValue(), nil
-}
-
-func Sign(h []byte, d *big.Int) (*big.Int, *big.Int, error) {
-	var r *big.Int
-	var s *big.Int
-	var err error
-
-	if len(h) != 32 {
-		return nil, nil, errors.New("invalid hash length")
-	}
-
-	for {
-		r, s, err = doSign(h, d)
-		if err != nil {
-			return nil, nil, err
-		}
-		if s.Cmp(big.NewInt(0)) != 0 {
-			break
-		}
-	}
-
-	return r, s, nil
-}
-
-func Verify(qX, qY, r, s *big.Int, h []byte) (*big.Int, error) {
-	var n = baseOrder
-	if r.Cmp(big.NewInt(0)) != 1 || r.Cmp(n) != -1 ||
-	   s.Cmp(big.NewInt(0)) != 1 || s.Cmp(n) != -1 {
-		// r and s must be in the interval [1,n-1].
-		return nil, errors.New("invalid signature")
-	}
-
-	// Use the fact that n is prime to define an ephemeral field
-	// and perform modulo arithmetic.
-	_, c, g := getCurve()
-	f := new(prime.Field).SetOrder(n)
-	q := c.NewPoint().Set(f.Element(qX), f.Element(qY))
-	e := new(big.Int).SetBytes(h)
-	sF := f.Element(s)
-	eF := f.Element(e)
-	rF := f.Element(r)
-
-	// The actual signature verification.
-	wF := f.NewElement().Div(f.Int64(1), sF)
-	u1 := f.NewElement().Mul(eF, wF)
-	u2 := f.NewElement().Mul(rF, wF)
-	A := c.NewPoint().Mul(g, u1.GetValue())
-	B := c.NewPoint().Mul(q, u2.GetValue())
-	C := c.NewPoint().Add(A, B)
-	v := C.GetX()
-
-	return v.Mod(v, n), nil
+var Params = &curve.Params {
+	Name: "secp256k1",
+	OID:  OID,
+	P:    fieldOrder,
+	N:    baseOrder,
+	A:    big.NewInt(0),
+	B:    big.NewInt(7),
+	Gx:   baseX,
+	Gy:   baseY,
 }
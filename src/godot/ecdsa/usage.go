@@ -15,37 +15,116 @@ func (k *ecdsa) UsageError() {
 
 The supported commands are:
 
-godot ecdsa new [-o <file>]
+godot ecdsa new [-c <curve>] [-f <format>] [--encrypt] [--pass <src>]
+    [--outform <form>] [-o <file>]
 
-	Creates a new secp256k1 private key. If -o is specified,
-	the key is written to <file> instead of stdout.
+	Creates a new ECDSA private key. If -c is specified, the key is
+	created on <curve>, which must be one of secp256k1, p256, p384
+	or p521; otherwise secp256k1 is used. If -f is specified,
+	<format> selects the form of the key: sec1 for a SEC1 "EC
+	PRIVATE KEY", pkcs8 for a PKCS#8 "PRIVATE KEY", or jwk for a
+	JSON Web Key; otherwise sec1 is used. All forms are accepted
+	wherever godot reads a private key. If --encrypt is specified,
+	the key is written as a PKCS#8 "ENCRYPTED PRIVATE KEY", using
+	PBES2 with PBKDF2-HMAC-SHA256 and AES-256-CBC, and -f, if
+	specified, must be pkcs8. The passphrase is read from <src> as
+	described below, or prompted for twice on the terminal if
+	--pass is not specified. If -o is specified, the key is written
+	to <file> instead of stdout.
 
-godot ecdsa pub [-i <file>] [-o <file>]
+godot ecdsa pub [--compressed] [-i <file>] [--inform <form>]
+    [--pass <src>] [-f <format>] [--outform <form>] [-o <file>]
 
-	Derives a public key from a private key. If -i is specified,
-	the key is read from <file> instead of stdin. The key must be
-	a secp256k1 ECDSA private key. If -o is specified, the public
-	key is written to <file> instead of stdout.
+	Derives a public key from a private key. If -i is specified, the
+	key is read from <file> instead of stdin. The key must be an
+	ECDSA private key on one of the supported curves. If -o is
+	specified, the public key is written to <file> instead of
+	stdout. If --compressed is specified, the public point is
+	written in compressed form. Both forms are accepted wherever
+	godot reads a key. If -f is specified, <format> selects the form
+	of the public key: x509 for a X.509 "PUBLIC KEY", or jwk for a
+	JSON Web Key, which is never compressed; otherwise x509 is used.
+	Both are accepted by verify. If the key is encrypted, the
+	passphrase is read from <src>, which is either env:<var> for the
+	environment variable <var>, or fd:<n> for the first line read
+	from file descriptor <n>; otherwise it is prompted for on the
+	terminal.
 
-godot ecdsa sign -k <file> [-i <file>] [-o <file>]
+godot ecdsa sign -k <file> [--inform <form>] [--pass <src>] [-r] [-R]
+    [--sig-encoding <enc>] [--sig-format <form>] [-i <file>] [-o <file>]
 
-	Generates a secp256k1 ECDSA signature with SHA-256 as the
-	digest mechanism. The -k parameter must be specified, and
-	<file> must point to a secp256k1 ECDSA private key. If -i is
-	specified, the contents to be signed are read from <file>
-	instead of stdin. If -o is specified, the resulting signature
-	is written to <file> instead of stdout. The signature is always
-	written in binary format.
+	Generates an ECDSA signature with SHA-256 as the digest
+	mechanism. The -k parameter must be specified, and <file> must
+	point to an ECDSA private key, whose passphrase is read from
+	<src> as described for pub. The curve is taken from the key.
+	Signatures are always normalised so that s is at most n/2.
+	Nonces are derived deterministically as specified in RFC 6979,
+	so signing the same contents twice yields the same signature. If
+	-r is specified, fresh random data is mixed into the nonce (RFC
+	6979, section 3.6) and signatures are no longer reproducible. If
+	-i is specified, the contents to be signed are read from <file>
+	instead of stdin. If -o is specified, the resulting signature is
+	written to <file> instead of stdout. If --sig-encoding is
+	specified, <enc> selects the encoding of the signature: der for
+	ASN.1 DER, or raw for r||s, with r and s encoded in the length
+	of the curve's order, as in IEEE P1363; otherwise der is used.
+	If -R is specified, a recoverable signature is written instead:
+	r||s followed by a single byte holding the recovery id (0 to 3).
+	On secp256k1, this is the 65-byte format used by Ethereum.
 
-godot ecdsa verify -k <file> -s <file> [-i <file>]
+godot ecdsa verify -k <file> [--inform <form>] -s <file>
+    [--sig-encoding <enc>] [--strict | --lax] [-i <file>]
 
-	Verifies a secp256k1 ECDSA signature with SHA-256 as the diges
+	Verifies an ECDSA signature with SHA-256 as the digest
 	mechanism. The -k and -s parameters must be specified and must
-	point to a secp256k1 ECDSA public key and signature
-	respectively. If -i is specified, the data whose signature is
-	being verified is read from <file> instead of stdin.
+	point to an ECDSA public key and signature respectively. The
+	curve is taken from the key. If --strict is specified,
+	signatures with s greater than n/2 or not encoded in strict DER
+	are rejected, as in BIP-62 and BIP-146. This is the default on
+	secp256k1, unless --lax is specified. If --sig-encoding is
+	specified, the signature is expected in the encoding <enc>, as
+	described for sign; otherwise der is expected. If -i is
+	specified, the data whose signature is being verified is read
+	from <file> instead of stdin.
 
---{binary,in,key,out} can be used instead of -{b,i,k,o}.
+godot ecdsa recover -s <file> [-c <curve>] [-i <file>] [-o <file>]
+
+	Recovers the public key from a recoverable signature created
+	with godot ecdsa sign -R. The -s parameter must be specified
+	and must point to the signature. The recovery id may also be
+	given as 27 to 30. If -c is specified, the signature is taken
+	to be on <curve>; otherwise secp256k1 is used. If -i is
+	specified, the signed data is read from <file> instead of
+	stdin. If -o is specified, the public key is written to <file>
+	instead of stdout.
+
+godot ecdsa sigconv [-c <curve>] [--sig-encoding <enc>]
+    [--sig-format <form>] [-i <file>] [-o <file>]
+
+	Converts an ECDSA signature between encodings, without the need
+	for a key. The signature is read in either encoding, and
+	written in the encoding <enc>, as described for sign; der is
+	used if --sig-encoding is not specified. Signatures with s
+	greater than n/2 are normalised to n - s, and DER is always
+	written in strict form. If -c is specified, the signature is
+	taken to be on <curve>; otherwise secp256k1 is used. If -i is
+	specified, the signature is read from <file> instead of stdin.
+	If -o is specified, the converted signature is written to
+	<file> instead of stdout.
+
+--inform and --outform select the form in which keys are read and
+written: der or pem. Keys are written in PEM format unless --outform
+der is specified, and read in either format unless --inform is
+specified.
+
+--sig-format selects the form in which signatures are written: binary,
+hex, base64, or pem for a "GODOT SIGNATURE" that also records the
+algorithm and hash used. Signatures are written in binary format
+unless --sig-format is specified, and read in any of these forms; the
+algorithm and hash recorded in a "GODOT SIGNATURE" must match.
+
+--{curve,format,in,key,out,randomize,recoverable,sig} can be used
+instead of -{c,f,i,k,o,r,R,s}.
 `)
 	os.Exit(1)
 }
@@ -0,0 +1,171 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package ed25519
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"godot/ed25519/edwards25519"
+	"godot/ed25519/rfc8410"
+	"godot/jwk"
+	"godot/util"
+	"io"
+	"os"
+)
+
+type ed25519 struct {
+	Private []byte // 32-byte seed
+	Public  []byte // 32-byte encoded point
+	format  string // "pkcs8" (the default) or "jwk"
+	pubFmt  string // "x509" (the default) or "jwk"
+}
+
+func New() *ed25519 {
+	return &ed25519{format: "pkcs8", pubFmt: "x509"}
+}
+
+// Option() parses Ed25519-specific options.
+func (ed *ed25519) Option(op string, args []string, i *int) bool {
+	switch {
+	case op == "new" && (args[*i] == "-f" || args[*i] == "--format"):
+		err := ed.SetFormat(util.GetArg(args, i))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	case op == "pub" && (args[*i] == "-f" || args[*i] == "--format"):
+		ed.pubFmt = util.GetArg(args, i)
+		if ed.pubFmt != "x509" && ed.pubFmt != "jwk" {
+			fmt.Fprintf(os.Stderr, "unsupported format\n")
+			os.Exit(1)
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
+// SetFormat() selects the form of new private keys: "pkcs8" or "jwk".
+func (ed *ed25519) SetFormat(f string) error {
+	if f != "pkcs8" && f != "jwk" {
+		return errors.New("unsupported format")
+	}
+	ed.format = f
+
+	return nil
+}
+
+// Describe() returns the names of the signature algorithm and hash, as
+// recorded in armored signatures.
+func (ed *ed25519) Describe() (alg, hash string) {
+	return "Ed25519", "SHA-512"
+}
+
+// JWSAlg() returns the JWS algorithm for Ed25519 keys.
+func (ed *ed25519) JWSAlg() string {
+	return "EdDSA"
+}
+
+// SetJWS() checks that alg is EdDSA, as per
+// https://tools.ietf.org/rfc/rfc8037.txt, section 3.1.
+func (ed *ed25519) SetJWS(alg string) error {
+	if alg != "EdDSA" {
+		return errors.New("unsupported algorithm for an Ed25519 key")
+	}
+
+	return nil
+}
+
+// NewKey() creates a new Ed25519 key pair and writes it to w in PEM
+// format. The key size is fixed, so l must be zero.
+func (ed *ed25519) NewKey(l int, w io.Writer) error {
+	if l != 0 {
+		return errors.New("key size is fixed")
+	}
+	seed, pub, err := edwards25519.NewPair()
+	if err != nil {
+		return err
+	}
+	ed.Private = seed
+	ed.Public = pub
+	if ed.format == "jwk" {
+		return ed.toJWK(false).Write(w)
+	}
+
+	return rfc8410.WritePriv(seed, w)
+}
+
+// LoadPriv() loads a private key from r, in PKCS#8 or JWK form.
+func (ed *ed25519) LoadPriv(r io.Reader) error {
+	body := util.ReadAll(r)
+	if jwk.IsKey(body) {
+		j, err := jwk.Read(bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		return ed.fromJWK(j, true)
+	}
+	seed, err := rfc8410.ReadPriv(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	pub, err := edwards25519.Public(seed)
+	if err != nil {
+		return err
+	}
+	ed.Private = seed
+	ed.Public = pub
+
+	return nil
+}
+
+// LoadPub() loads a public key from r, in X.509 or JWK form.
+func (ed *ed25519) LoadPub(r io.Reader) error {
+	body := util.ReadAll(r)
+	if jwk.IsKey(body) {
+		j, err := jwk.Read(bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		return ed.fromJWK(j, false)
+	}
+	pub, err := rfc8410.ReadPub(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	ed.Public = pub
+
+	return nil
+}
+
+// WritePub() writes a public key to w, in X.509 form unless a JWK was
+// requested.
+func (ed *ed25519) WritePub(w io.Writer) error {
+	if ed.pubFmt == "jwk" {
+		return ed.toJWK(true).Write(w)
+	}
+
+	return rfc8410.WritePub(ed.Public, w)
+}
+
+// Sign() generates a signature of m and writes it to w. Ed25519 hashes
+// the message itself, so m is read in its entirety.
+func (ed *ed25519) Sign(m io.Reader, w io.Writer) error {
+	sig, err := edwards25519.Sign(ed.Private, util.ReadAll(m))
+	if err != nil {
+		return err
+	}
+	w.Write(sig)
+
+	return nil
+}
+
+// Verify() checks if t is a valid signature of m.
+func (ed *ed25519) Verify(t, m io.Reader) (bool, error) {
+	sig := util.ReadAll(t)
+	return edwards25519.Verify(ed.Public, util.ReadAll(m), sig)
+}
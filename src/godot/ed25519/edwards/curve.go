@@ -0,0 +1,187 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// curve.go implements twisted Edwards curve arithmetic over prime
+// fields. The field arithmetic itself is borrowed from ecdsa/prime.
+
+package edwards
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"godot/ecdsa/prime"
+	"math/big"
+)
+
+// A twisted Edwards curve of the form a*x^2 + y^2 = 1 + d*x^2*y^2.
+type Curve struct {
+	f   *prime.Field   // over which the curve is defined
+	a,d *prime.Element // coefficients
+	l   int            // bytes in a field element
+}
+
+// Unlike Weierstrass curves, Edwards curves have no point at infinity;
+// the neutral element is (0,1).
+type Point struct {
+	c   *Curve         // associated curve
+	x,y *prime.Element // point coordinates
+}
+
+func (c *Curve) Define(f *prime.Field, a, d *prime.Element) *Curve {
+	c.f = f
+	c.a = a
+	c.d = d
+	c.l = (f.NewElement().Neg(f.Int64(1)).GetValue().BitLen() + 7) / 8
+	return c
+}
+
+func (c *Curve) GetD() *prime.Element {
+	return c.d
+}
+
+func (c *Curve) NewPoint() *Point {
+	return new(Point).SetCurve(c)
+}
+
+// IsOnCurve() checks whether (x,y) satisfies the curve equation.
+func (c *Curve) IsOnCurve(x, y *prime.Element) bool {
+	f := c.f
+	xx := f.NewElement().Mul(x, x)
+	yy := f.NewElement().Mul(y, y)
+	l := f.NewElement().Mul(c.a, xx)      // a*x^2
+	l.Add(l, yy)                          // a*x^2 + y^2
+	r := f.NewElement().Mul(c.d, xx)      // d*x^2
+	r.Mul(r, yy)                          // d*x^2*y^2
+	r.Add(r, f.Int64(1))                  // 1 + d*x^2*y^2
+	return l.Cmp(r) == 0
+}
+
+func (p *Point) SetCurve(c *Curve) *Point {
+	p.c = c
+	return p
+}
+
+func (p *Point) Set(x, y *prime.Element) *Point {
+	if p.c.IsOnCurve(x, y) == false {
+		panic("point not on curve")
+	}
+	return p.set(x, y)
+}
+
+// set() is like Set(), but does not check whether (x,y) is on the
+// curve. It is used for the results of point arithmetic.
+func (p *Point) set(x, y *prime.Element) *Point {
+	p.x = x
+	p.y = y
+	return p
+}
+
+func (p *Point) SetZero() *Point {
+	f := p.c.f
+	p.x = f.Int64(0)
+	p.y = f.Int64(1)
+	return p
+}
+
+func (p *Point) GetX() *big.Int {
+	return p.x.GetValue()
+}
+
+func (p *Point) GetY() *big.Int {
+	return p.y.GetValue()
+}
+
+func (p *Point) Neg(t *Point) *Point {
+	f := t.c.f
+	return p.set(f.NewElement().Neg(t.x), t.y)
+}
+
+func (p *Point) Equal(t *Point) bool {
+	return p.x.Cmp(t.x) == 0 && p.y.Cmp(t.y) == 0
+}
+
+func (p *Point) String() string {
+	return fmt.Sprintf("(%s,%s)", p.x, p.y)
+}
+
+// The addition law below is the unified one given in Twisted Edwards
+// Curves by Bernstein, Birkner, Joye, Lange & Peters, section 6. It
+// is complete for the curves we use (a square, d a non-square), so
+// doubling is merely the addition of a point to itself.
+
+// Section 6
+func (p *Point) Add(t, u *Point) *Point {
+	c := t.c
+	f := c.f
+	k := f.NewElement().Mul(t.x, u.x)     // x1*x2
+	l := f.NewElement().Mul(t.y, u.y)     // y1*y2
+	m := f.NewElement().Mul(c.d, k)
+	m.Mul(m, l)                           // d*x1*x2*y1*y2
+
+	x := f.NewElement().Mul(t.x, u.y)
+	x.Add(x, f.NewElement().Mul(t.y, u.x))
+	x.Div(x, f.NewElement().Add(f.Int64(1), m))
+
+	y := f.NewElement().Mul(c.a, k)
+	y.Sub(l, y)
+	y.Div(y, f.NewElement().Sub(f.Int64(1), m))
+
+	return p.set(x, y)
+}
+
+func (p *Point) Double(t *Point) *Point {
+	return p.Add(t, t)
+}
+
+// Algorithm 3.26 of Guide to Elliptic Curve Cryptography, adapted to
+// a neutral element of (0,1). Mul() branches on the bits of k, and
+// must therefore only be used with public scalars; see MulSecret().
+func (p *Point) Mul(t *Point, k *big.Int) *Point {
+	c := t.c
+	u := c.NewPoint().set(t.x, t.y)
+	p.SetZero()
+
+	for i := 0; i < k.BitLen(); i++ {
+		if k.Bit(i) == 1 {
+			p.Add(p, u)
+		}
+		u.Double(u)
+	}
+
+	return p
+}
+
+// MulSecret() computes k*t, where k is a secret scalar in [0,n) and n
+// is the order of t, using the Montgomery ladder in extended
+// coordinates (see extended.go). Every bit of k results in the same
+// sequence of operations: two additions and two conditional swaps.
+// The bit length of k is masked by adding n (or 2n) to it beforehand.
+// Note that Go's big.Int arithmetic is not itself constant-time, so
+// this is a best effort.
+func (p *Point) MulSecret(t *Point, k, n *big.Int) *Point {
+	// k + n and k + 2n are both congruent to k modulo n. Pick the
+	// one whose bit length is that of n plus one. k + 2n may need
+	// two more bits than n, and both are encoded.
+	nBits := n.BitLen()
+	l := (nBits + 2 + 7) / 8
+	k1 := new(big.Int).Add(k, n)
+	k2 := new(big.Int).Add(k1, n)
+	b1 := k1.FillBytes(make([]byte, l))
+	b2 := k2.FillBytes(make([]byte, l))
+	subtle.ConstantTimeCopy(1 - int(k1.Bit(nBits)), b1, b2)
+	kk := new(big.Int).SetBytes(b1)
+
+	// The top bit of kk is set, so the ladder starts at (t,2t).
+	r0 := t.toExtended()
+	r1 := new(extended).add(r0, r0)
+	for i := nBits - 1; i >= 0; i-- {
+		b := kk.Bit(i)
+		r0.cswap(r1, b)
+		r1.add(r0, r1)
+		r0.add(r0, r0)
+		r0.cswap(r1, b)
+	}
+
+	return r0.toAffine(p)
+}
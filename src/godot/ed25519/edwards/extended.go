@@ -0,0 +1,113 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// extended.go implements twisted Edwards curve arithmetic in extended
+// coordinates, where a point (X,Y,Z,T) corresponds to the affine point
+// (X/Z,Y/Z), with T = X*Y/Z. Points are only converted back to affine
+// coordinates at the end of a computation, and no operation depends on
+// the value of its operands.
+
+package edwards
+
+import (
+	"crypto/subtle"
+	"godot/ecdsa/prime"
+	"math/big"
+)
+
+type extended struct {
+	c       *Curve         // associated curve
+	x,y,z,t *prime.Element // projective coordinates
+}
+
+// extendedZero() returns the neutral element, (0,1,1,0).
+func (c *Curve) extendedZero() *extended {
+	f := c.f
+	return &extended{c, f.Int64(0), f.Int64(1), f.Int64(1), f.Int64(0)}
+}
+
+// toExtended() maps an affine point p to (x,y,1,x*y).
+func (p *Point) toExtended() *extended {
+	c := p.c
+	f := c.f
+	x := f.Element(new(big.Int).Set(p.x.GetValue()))
+	y := f.Element(new(big.Int).Set(p.y.GetValue()))
+	t := f.NewElement().Mul(x, y)
+	return &extended{c, x, y, f.Int64(1), t}
+}
+
+func (e *extended) set(t *extended) *extended {
+	e.c, e.x, e.y, e.z, e.t = t.c, t.x, t.y, t.z, t.t
+	return e
+}
+
+// toAffine() maps (X,Y,Z,T) to (X/Z,Y/Z), with a single inversion.
+func (e *extended) toAffine(p *Point) *Point {
+	c := e.c
+	f := c.f
+	p.c = c
+	zInv := f.NewElement().Div(f.Int64(1), e.z)
+
+	return p.set(f.NewElement().Mul(e.x, zInv),
+	    f.NewElement().Mul(e.y, zInv))
+}
+
+// Twisted Edwards Curves Revisited by Hisil, Wong, Carter & Dawson,
+// section 3.1 (unified addition):
+// A = X1*X2, B = Y1*Y2, C = d*T1*T2, D = Z1*Z2,
+// E = (X1+Y1)*(X2+Y2) - A - B, F = D - C, G = D + C, H = B - a*A,
+// X3 = E*F, Y3 = G*H, T3 = E*H, Z3 = F*G.
+// Like the affine addition law, it is complete for the curves we use,
+// so it also serves for doubling.
+func (e *extended) add(t, u *extended) *extended {
+	c := t.c
+	f := c.f
+	a := f.NewElement().Mul(t.x, u.x)
+	b := f.NewElement().Mul(t.y, u.y)
+	cc := f.NewElement().Mul(c.d, t.t)
+	cc.Mul(cc, u.t)
+	d := f.NewElement().Mul(t.z, u.z)
+
+	ee := f.NewElement().Add(t.x, t.y)
+	ee.Mul(ee, f.NewElement().Add(u.x, u.y))
+	ee.Sub(ee, a)
+	ee.Sub(ee, b)
+	ff := f.NewElement().Sub(d, cc)
+	g := f.NewElement().Add(d, cc)
+	h := f.NewElement().Mul(c.a, a)
+	h.Sub(b, h)
+
+	x := f.NewElement().Mul(ee, ff)
+	y := f.NewElement().Mul(g, h)
+	z := f.NewElement().Mul(ff, g)
+	tt := f.NewElement().Mul(ee, h)
+
+	return e.set(&extended{c, x, y, z, tt})
+}
+
+// cswap() swaps the coordinates of e and t if swap is 1, and leaves
+// them untouched if swap is 0. The same operations are performed in
+// both cases. Fresh elements are always allocated, so that elements
+// shared with other points are never modified.
+func (e *extended) cswap(t *extended, swap uint) {
+	l := e.c.l
+	mask := byte(subtle.ConstantTimeByteEq(uint8(swap), 1) * 0xff)
+	a := []*prime.Element{e.x, e.y, e.z, e.t}
+	b := []*prime.Element{t.x, t.y, t.z, t.t}
+
+	for i := range a {
+		x := a[i].GetValue().FillBytes(make([]byte, l))
+		y := b[i].GetValue().FillBytes(make([]byte, l))
+		for k := range x {
+			d := mask & (x[k] ^ y[k])
+			x[k] ^= d
+			y[k] ^= d
+		}
+		a[i] = e.c.f.Element(new(big.Int).SetBytes(x))
+		b[i] = e.c.f.Element(new(big.Int).SetBytes(y))
+	}
+
+	e.x, e.y, e.z, e.t = a[0], a[1], a[2], a[3]
+	t.x, t.y, t.z, t.t = b[0], b[1], b[2], b[3]
+}
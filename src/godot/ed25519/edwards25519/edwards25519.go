@@ -0,0 +1,264 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// The edwards25519 module implements Ed25519 as specified in
+// RFC 8032, section 5.1. Only the pure variant (no prehashing,
+// no context) is supported.
+
+package edwards25519
+
+import (
+	"encoding/asn1"
+	"errors"
+	"godot/ecdsa/prime"
+	"godot/ed25519/edwards"
+	"godot/rand"
+	"godot/sha512"
+	"math/big"
+)
+
+const (
+	KeyLen = 32 // bytes in a private seed or public key
+	SigLen = 64 // bytes in a signature
+)
+
+var OID asn1.ObjectIdentifier = []int{1, 3, 101, 112}
+
+var (
+	ErrBadPoint = errors.New("edwards25519: invalid point")
+	ErrBadKey   = errors.New("edwards25519: invalid key")
+)
+
+// The order of the prime field over which edwards25519 is defined:
+// 2^255 - 19.
+var fieldOrder = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255),
+    big.NewInt(19))
+
+// The order of the base point B: 2^252 +
+// 27742317777372353535851937790883648493.
+var baseOrder = new(big.Int).SetBytes([]byte {
+	0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x14, 0xde, 0xf9, 0xde, 0xa2, 0xf7, 0x9c, 0xd6,
+	0x58, 0x12, 0x63, 0x1a, 0x5c, 0xf5, 0xd3, 0xed,
+})
+
+// The x-coordinate of the base point B.
+var baseX = new(big.Int).SetBytes([]byte {
+	0x21, 0x69, 0x36, 0xd3, 0xcd, 0x6e, 0x53, 0xfe,
+	0xc0, 0xa4, 0xe2, 0x31, 0xfd, 0xd6, 0xdc, 0x5c,
+	0x69, 0x2c, 0xc7, 0x60, 0x95, 0x25, 0xa7, 0xb2,
+	0xc9, 0x56, 0x2d, 0x60, 0x8f, 0x25, 0xd5, 0x1a,
+})
+
+// The y-coordinate of the base point B: 4/5.
+var baseY = new(big.Int).SetBytes([]byte {
+	0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+	0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+	0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66,
+	0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x66, 0x58,
+})
+
+// getCurve() instantiates edwards25519's parameters (field, curve,
+// and base point). The curve is -x^2 + y^2 = 1 + d*x^2*y^2, where
+// d = -121665/121666.
+func getCurve() (*prime.Field, *edwards.Curve, *edwards.Point) {
+	f := new(prime.Field).SetOrder(fieldOrder)
+	a := f.NewElement().Neg(f.Int64(1))
+	d := f.NewElement().Neg(f.Int64(121665))
+	d.Div(d, f.Int64(121666))
+	c := new(edwards.Curve).Define(f, a, d)
+	b := c.NewPoint().Set(f.Element(baseX), f.Element(baseY))
+	return f, c, b
+}
+
+// reverse() returns a copy of p with its bytes in reverse order.
+func reverse(p []byte) []byte {
+	r := make([]byte, len(p))
+	for i := range p {
+		r[len(p) - 1 - i] = p[i]
+	}
+	return r
+}
+
+// fromLE() interprets p as a little-endian integer.
+func fromLE(p []byte) *big.Int {
+	return new(big.Int).SetBytes(reverse(p))
+}
+
+// toLE() encodes v as a little-endian integer of n bytes.
+func toLE(v *big.Int, n int) []byte {
+	b := v.Bytes()
+	p := make([]byte, n)
+	copy(p[n - len(b):], b)
+	return reverse(p)
+}
+
+// encode() transforms a point into its 32-byte representation
+// (section 5.1.2).
+func encode(q *edwards.Point) []byte {
+	p := toLE(q.GetY(), KeyLen)
+	p[KeyLen - 1] |= byte(q.GetX().Bit(0) << 7)
+	return p
+}
+
+// decode() transforms a 32-byte representation into a point (section
+// 5.1.3).
+func decode(p []byte) (*edwards.Point, error) {
+	if len(p) != KeyLen {
+		return nil, ErrBadPoint
+	}
+	f, c, _ := getCurve()
+	b := make([]byte, KeyLen)
+	copy(b, p)
+	sign := uint(b[KeyLen - 1] >> 7)
+	b[KeyLen - 1] &= 0x7f
+	yv := fromLE(b)
+	if yv.Cmp(fieldOrder) != -1 {
+		return nil, ErrBadPoint
+	}
+
+	// x^2 = (y^2 - 1) / (d*y^2 + 1); x is a candidate root, which
+	// may need to be multiplied by sqrt(-1).
+	y := f.Element(yv)
+	yy := f.NewElement().Mul(y, y)
+	u := f.NewElement().Sub(yy, f.Int64(1))
+	v := f.NewElement().Mul(c.GetD(), yy)
+	v.Add(v, f.Int64(1))
+	w := f.NewElement().Div(u, v)
+	e := new(big.Int).Add(fieldOrder, big.NewInt(3))
+	x := f.NewElement().Exp(w, f.Element(e.Rsh(e, 3)))
+	xx := f.NewElement().Mul(x, x)
+	if xx.Cmp(w) != 0 {
+		if xx.Cmp(f.NewElement().Neg(w)) != 0 {
+			return nil, ErrBadPoint
+		}
+		e = new(big.Int).Sub(fieldOrder, big.NewInt(1))
+		i := f.NewElement().Exp(f.Int64(2), f.Element(e.Rsh(e, 2)))
+		x.Mul(x, i)
+	}
+	if x.GetValue().Sign() == 0 && sign == 1 {
+		return nil, ErrBadPoint
+	}
+	if x.GetValue().Bit(0) != sign {
+		x = f.NewElement().Neg(x)
+	}
+
+	return c.NewPoint().Set(x, y), nil
+}
+
+// expand() hashes a 32-byte seed, returning the clamped secret scalar
+// s and the prefix used in nonce generation (section 5.1.5).
+func expand(seed []byte) (*big.Int, []byte, error) {
+	if len(seed) != KeyLen {
+		return nil, nil, ErrBadKey
+	}
+	h, err := sha512.DigestBytes(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+	h[0] &= 0xf8
+	h[31] &= 0x7f
+	h[31] |= 0x40
+
+	return fromLE(h[:32]), h[32:], nil
+}
+
+// hashScalar() returns SHA-512(p[0] || p[1] || ...) mod L.
+func hashScalar(p ...[]byte) (*big.Int, error) {
+	var m []byte
+	for _, b := range p {
+		m = append(m, b...)
+	}
+	h, err := sha512.DigestBytes(m)
+	if err != nil {
+		return nil, err
+	}
+	k := fromLE(h)
+
+	return k.Mod(k, baseOrder), nil
+}
+
+// NewPair() returns a new private seed and its matching public key.
+func NewPair() ([]byte, []byte, error) {
+	seed, err := rand.Bytes(KeyLen)
+	if err != nil {
+		return nil, nil, err
+	}
+	pub, err := Public(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return seed, pub, nil
+}
+
+// Public() derives the public key of a private seed.
+func Public(seed []byte) ([]byte, error) {
+	s, _, err := expand(seed)
+	if err != nil {
+		return nil, err
+	}
+	_, c, b := getCurve()
+	a := c.NewPoint().MulSecret(b, s.Mod(s, baseOrder), baseOrder)
+
+	return encode(a), nil
+}
+
+// Sign() implements the Ed25519 signing operation (section 5.1.6).
+func Sign(seed, m []byte) ([]byte, error) {
+	s, prefix, err := expand(seed)
+	if err != nil {
+		return nil, err
+	}
+	_, c, b := getCurve()
+	s.Mod(s, baseOrder)
+	a := encode(c.NewPoint().MulSecret(b, s, baseOrder))
+	r, err := hashScalar(prefix, m)
+	if err != nil {
+		return nil, err
+	}
+	R := encode(c.NewPoint().MulSecret(b, r, baseOrder))
+	k, err := hashScalar(R, a, m)
+	if err != nil {
+		return nil, err
+	}
+
+	// S = (r + k*s) mod L
+	S := new(big.Int).Mul(k, s)
+	S.Add(S, r)
+	S.Mod(S, baseOrder)
+
+	return append(R, toLE(S, KeyLen)...), nil
+}
+
+// Verify() implements the Ed25519 verification operation (section
+// 5.1.7), checking [S]B = R + [k]A.
+func Verify(pub, m, sig []byte) (bool, error) {
+	if len(sig) != SigLen {
+		return false, errors.New("invalid signature")
+	}
+	A, err := decode(pub)
+	if err != nil {
+		return false, err
+	}
+	R, err := decode(sig[:KeyLen])
+	if err != nil {
+		return false, nil
+	}
+	S := fromLE(sig[KeyLen:])
+	if S.Cmp(baseOrder) != -1 {
+		return false, nil
+	}
+	k, err := hashScalar(sig[:KeyLen], pub, m)
+	if err != nil {
+		return false, err
+	}
+
+	_, c, b := getCurve()
+	l := c.NewPoint().Mul(b, S)
+	r := c.NewPoint().Add(R, c.NewPoint().Mul(A, k))
+
+	return l.Equal(r), nil
+}
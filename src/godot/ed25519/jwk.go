@@ -0,0 +1,63 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// This file maps Ed25519 keys to and from JSON Web Keys, as specified
+// in RFC 8037, section 2.
+
+package ed25519
+
+import (
+	"bytes"
+	"errors"
+	"godot/ed25519/edwards25519"
+	"godot/jwk"
+)
+
+// toJWK() converts the key pair into a JWK. If pub is true, only the
+// public members are set.
+func (ed *ed25519) toJWK(pub bool) *jwk.Key {
+	var j = new(jwk.Key)
+
+	j.Kty = "OKP"
+	j.Crv = "Ed25519"
+	j.X = jwk.EncodeBytes(ed.Public)
+	if pub == false {
+		j.D = jwk.EncodeBytes(ed.Private)
+	}
+
+	return j
+}
+
+// fromJWK() loads the members of a JWK. If priv is true, the private
+// key must be present, and must match the public key.
+func (ed *ed25519) fromJWK(j *jwk.Key, priv bool) error {
+	if j.Kty != "OKP" || j.Crv != "Ed25519" {
+		return errors.New("not an Ed25519 key")
+	}
+	pub, err := jwk.DecodeBytes(j.X, edwards25519.KeyLen)
+	if err != nil {
+		return err
+	}
+	ed.Public = pub
+	if priv == false {
+		return nil
+	}
+	if j.IsPrivate() == false {
+		return errors.New("not a private key")
+	}
+	seed, err := jwk.DecodeBytes(j.D, edwards25519.KeyLen)
+	if err != nil {
+		return err
+	}
+	pub, err = edwards25519.Public(seed)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(pub, ed.Public) == false {
+		return jwk.ErrBadKey
+	}
+	ed.Private = seed
+
+	return nil
+}
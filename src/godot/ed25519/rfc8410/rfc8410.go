@@ -0,0 +1,151 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// The rfc8410 module exports functions that allow Ed25519 private
+// keys (in PKCS#8 form) and public keys (in X.509 form) to be read
+// and written.
+
+package rfc8410
+
+import (
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"godot/ed25519/edwards25519"
+	"godot/util"
+	"io"
+	"io/ioutil"
+	"math/big"
+)
+
+var (
+	ErrPemDecode = errors.New("rfc8410: pem decode error")
+	ErrBadPem    = errors.New("rfc8410: invalid pem")
+	ErrBadKey    = errors.New("rfc8410: invalid key")
+)
+
+// As per https://tools.ietf.org/rfc/rfc8410.txt, section 3. The
+// parameters must be absent.
+type AlgorithmIdentifier struct {
+	Algorithm	asn1.ObjectIdentifier
+}
+
+// As per https://tools.ietf.org/rfc/rfc8410.txt, section 7.
+type PrivateKey struct {
+	Version		*big.Int
+	Algorithm	AlgorithmIdentifier
+	PrivateKey	[]byte // a DER-encoded CurvePrivateKey
+}
+
+// As per https://tools.ietf.org/rfc/rfc8410.txt, section 4.
+type PublicKey struct {
+	Algorithm	AlgorithmIdentifier
+	PublicKey	asn1.BitString
+}
+
+// readPem() reads a PEM block of type t from r. DER is also accepted.
+func readPem(r io.Reader, t string) ([]byte, error) {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	der, bt, ok := util.Unarmor(body)
+	if ok == false {
+		return nil, ErrPemDecode
+	}
+	if (bt != "" && bt != t) || der == nil {
+		return nil, ErrBadPem
+	}
+
+	return der, nil
+}
+
+// writePem() writes v as a PEM block of type t.
+func writePem(v interface{}, t string, w io.Writer) error {
+	var blob = new(pem.Block)
+	var err error
+
+	blob.Type = t
+	blob.Bytes, err = asn1.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return pem.Encode(w, blob)
+}
+
+// WritePriv() writes an Ed25519 private seed in PKCS#8 PEM format.
+func WritePriv(seed []byte, w io.Writer) error {
+	var k = new(PrivateKey)
+	var err error
+
+	k.Version = big.NewInt(0)
+	k.Algorithm.Algorithm = edwards25519.OID
+	k.PrivateKey, err = asn1.Marshal(seed)
+	if err != nil {
+		return err
+	}
+
+	return writePem(*k, "PRIVATE KEY", w)
+}
+
+// ReadPriv() reads an Ed25519 private seed in PKCS#8 PEM format.
+func ReadPriv(r io.Reader) ([]byte, error) {
+	var k = new(PrivateKey)
+	var seed []byte
+
+	body, err := readPem(r, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	_, err = asn1.Unmarshal(body, k)
+	if err != nil {
+		return nil, err
+	}
+	if k.Version == nil || k.Version.Sign() != 0 ||
+	   edwards25519.OID.Equal(k.Algorithm.Algorithm) == false {
+		return nil, ErrBadKey
+	}
+	rest, err := asn1.Unmarshal(k.PrivateKey, &seed)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 || len(seed) != edwards25519.KeyLen {
+		return nil, ErrBadKey
+	}
+
+	return seed, nil
+}
+
+// WritePub() writes an Ed25519 public key in X.509 PEM format.
+func WritePub(pub []byte, w io.Writer) error {
+	var k = new(PublicKey)
+
+	k.Algorithm.Algorithm = edwards25519.OID
+	k.PublicKey.Bytes = pub
+	k.PublicKey.BitLength = 8 * len(pub)
+
+	return writePem(*k, "PUBLIC KEY", w)
+}
+
+// ReadPub() reads an Ed25519 public key in X.509 PEM format.
+func ReadPub(r io.Reader) ([]byte, error) {
+	var k = new(PublicKey)
+
+	body, err := readPem(r, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+	_, err = asn1.Unmarshal(body, k)
+	if err != nil {
+		return nil, err
+	}
+	if edwards25519.OID.Equal(k.Algorithm.Algorithm) == false ||
+	   len(k.PublicKey.Bytes) != edwards25519.KeyLen ||
+	   k.PublicKey.BitLength != 8 * edwards25519.KeyLen {
+		return nil, ErrBadKey
+	}
+
+	return k.PublicKey.Bytes, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package ed25519
+
+import (
+	"fmt"
+	"os"
+)
+
+func (ed *ed25519) UsageError() {
+	fmt.Fprintf(os.Stderr,
+`usage: godot ed25519 [command] [arguments]
+
+The supported commands are:
+
+godot ed25519 new [-f <format>] [--outform <form>] [-o <file>]
+
+	Creates a new Ed25519 private key. If -o is specified, the key
+	is written to <file> instead of stdout. If -f is specified,
+	<format> selects the form of the key: pkcs8 for a PKCS#8
+	"PRIVATE KEY", or jwk for a JSON Web Key; otherwise pkcs8 is
+	used. Both forms are accepted wherever godot reads a private
+	key.
+
+godot ed25519 pub [-i <file>] [--inform <form>] [-f <format>]
+    [--outform <form>] [-o <file>]
+
+	Derives a public key from a private key. If -i is specified,
+	the key is read from <file> instead of stdin. The key must be
+	an Ed25519 private key. If -o is specified, the public key is
+	written to <file> instead of stdout. If -f is specified,
+	<format> selects the form of the public key: x509 for a X.509
+	"PUBLIC KEY", or jwk for a JSON Web Key; otherwise x509 is
+	used. Both are accepted by verify.
+
+godot ed25519 sign -k <file> [--inform <form>] [--sig-format <form>]
+    [-i <file>] [-o <file>]
+
+	Generates an Ed25519 signature as specified in RFC 8032. The
+	-k parameter must be specified, and <file> must point to an
+	Ed25519 private key. If -i is specified, the contents to be
+	signed are read from <file> instead of stdin. If -o is
+	specified, the resulting signature is written to <file> instead
+	of stdout.
+
+godot ed25519 verify -k <file> [--inform <form>] -s <file> [-i <file>]
+
+	Verifies an Ed25519 signature. The -k and -s parameters must
+	be specified and must point to an Ed25519 public key and
+	signature respectively. If -i is specified, the data whose
+	signature is being verified is read from <file> instead of
+	stdin.
+
+--inform and --outform select the form in which keys are read and
+written: der or pem. Keys are written in PEM format unless --outform
+der is specified, and read in either format unless --inform is
+specified.
+
+--sig-format selects the form in which signatures are written: binary,
+hex, base64, or pem for a "GODOT SIGNATURE" that also records the
+algorithm and hash used. Signatures are written in binary format
+unless --sig-format is specified, and read in any of these forms; the
+algorithm and hash recorded in a "GODOT SIGNATURE" must match.
+
+--{format,in,key,out,sig} can be used instead of -{f,i,k,o,s}.
+`)
+	os.Exit(1)
+}
@@ -5,9 +5,16 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"godot/ecdsa"
+	"godot/ed25519"
+	"godot/jwk"
+	"godot/jwt"
+	"godot/pkcs8"
 	"godot/rsa"
+	"godot/schnorr"
 	"godot/sha256"
 	"godot/util"
 	"io"
@@ -21,9 +28,35 @@ type sigAlg interface {
 	WritePub(w io.Writer) error
 	Sign(m io.Reader, w io.Writer) error
 	Verify(t, m io.Reader) (bool, error)
+	Option(op string, args []string, i *int) bool
+	Describe() (alg, hash string)
 	UsageError()
 }
 
+// A sigAlg may also implement recoverer, in which case the public key
+// can be recovered from a signature.
+type recoverer interface {
+	Recover(t, m io.Reader, w io.Writer) error
+}
+
+// A sigAlg may also implement checker, in which case the consistency
+// of a private key can be checked.
+type checker interface {
+	Check() []error
+}
+
+// A sigAlg may also implement converter, in which case signatures can
+// be converted between encodings without a key.
+type converter interface {
+	Convert(t io.Reader, w io.Writer) error
+}
+
+// A sigAlg may also implement formatter, in which case the form of
+// new private keys can be chosen. "pkcs8" must be supported.
+type formatter interface {
+	SetFormat(f string) error
+}
+
 func usageError() {
 	fmt.Fprintf(os.Stderr,
 `godot implements digital signature primitives.
@@ -34,8 +67,12 @@ Usage:
 
 The commands are:
 
-    ecdsa	perform secp256k1 ECDSA operations
-    rsa		perform 4096-bit RSA operations
+    ecdsa	perform ECDSA operations
+    ed25519	perform Ed25519 operations
+    jwk		compute JSON Web Key thumbprints
+    jwt		sign and verify JSON Web Tokens
+    rsa		perform RSA operations
+    schnorr	perform BIP-340 Schnorr operations
     sha256	calculate a SHA-256 digest
     version	print godot's version number
 
@@ -48,27 +85,104 @@ func printVersion() {
 	fmt.Fprintf(os.Stdout, "godot 1.0\n")
 }
 
+// loadPriv() loads a private key in the given form from in, decrypting
+// it first if needed. The passphrase is obtained from src, as per
+// util.ReadPass().
+func loadPriv(a sigAlg, in io.Reader, form, src string) error {
+	body := util.ReadAll(in)
+	err := util.CheckForm(body, form)
+	if err != nil {
+		return err
+	}
+	body, err = pkcs8.DecryptPEM(body, func() []byte {
+		return util.ReadPass(src, false)
+	})
+	if err != nil {
+		return err
+	}
+
+	return a.LoadPriv(bytes.NewReader(body))
+}
+
+// loadPub() loads a public key in the given form from in.
+func loadPub(a sigAlg, in io.Reader, form string) error {
+	body := util.ReadAll(in)
+	err := util.CheckForm(body, form)
+	if err != nil {
+		return err
+	}
+
+	return a.LoadPub(bytes.NewReader(body))
+}
+
 func NewKey(args []string, a sigAlg) error {
 	var out *os.File = os.Stdout
+	var l int = 0 // let the algorithm pick
+	var encrypt bool
+	var pass string
+	var format, outform string
 
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
+		case "-b":
+			fallthrough
+		case "--bits":
+			l = util.GetIntArg(args, &i)
+		case "--encrypt":
+			encrypt = true
+		case "--pass":
+			pass = util.GetArg(args, &i)
+		case "--outform":
+			outform = util.GetFormArg(args, &i)
 		case "-o":
 			fallthrough
 		case "--out":
 			util.CreateFile(&out, os.Stdout,
 			    util.GetArg(args, &i))
 		default:
-			a.UsageError()
+			opt := args[i]
+			if a.Option("new", args, &i) == false {
+				a.UsageError()
+			}
+			if opt == "-f" || opt == "--format" {
+				format = args[i]
+			}
 		}
 	}
 
-	return a.NewKey(4096, out)
+	// Only PKCS#8 keys can be encrypted. Unless another format was
+	// asked for, pick it.
+	if encrypt && format != "" && format != "pkcs8" {
+		return errors.New("--encrypt requires -f pkcs8")
+	}
+	if f, ok := a.(formatter); ok && encrypt && format == "" {
+		err := f.SetFormat("pkcs8")
+		if err != nil {
+			return err
+		}
+	}
+	body := new(bytes.Buffer)
+	err := a.NewKey(l, body)
+	if err != nil {
+		return err
+	}
+	if encrypt {
+		plain := body.Bytes()
+		body = new(bytes.Buffer)
+		err = pkcs8.EncryptPEM(plain, util.ReadPass(pass, true), body)
+		if err != nil {
+			return err
+		}
+	}
+
+	return util.WriteForm(out, body.Bytes(), outform)
 }
 
 func PubKey(args []string, a sigAlg) error {
 	var in  *os.File = os.Stdin
 	var out *os.File = os.Stdout
+	var pass string
+	var inform, outform string
 
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -82,23 +196,39 @@ func PubKey(args []string, a sigAlg) error {
 		case "--out":
 			util.CreateFile(&out, os.Stdout,
 			    util.GetArg(args, &i))
+		case "--pass":
+			pass = util.GetArg(args, &i)
+		case "--inform":
+			inform = util.GetFormArg(args, &i)
+		case "--outform":
+			outform = util.GetFormArg(args, &i)
 		default:
-			a.UsageError()
+			if a.Option("pub", args, &i) == false {
+				a.UsageError()
+			}
 		}
 	}
 
-	err := a.LoadPriv(in)
+	err := loadPriv(a, in, inform, pass)
+	if err != nil {
+		return err
+	}
+	body := new(bytes.Buffer)
+	err = a.WritePub(body)
 	if err != nil {
 		return err
 	}
 
-	return a.WritePub(out)
+	return util.WriteForm(out, body.Bytes(), outform)
 }
 
 func Sign(args []string, a sigAlg) error {
 	var in  *os.File = os.Stdin
 	var out *os.File = os.Stdout
 	var key *os.File
+	var pass string
+	var inform string
+	var sigform string
 
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -117,8 +247,16 @@ func Sign(args []string, a sigAlg) error {
 		case "--out":
 			util.CreateFile(&out, os.Stdout,
 			    util.GetArg(args, &i))
+		case "--pass":
+			pass = util.GetArg(args, &i)
+		case "--inform":
+			inform = util.GetFormArg(args, &i)
+		case "--sig-format":
+			sigform = util.GetSigFormArg(args, &i)
 		default:
-			a.UsageError()
+			if a.Option("sign", args, &i) == false {
+				a.UsageError()
+			}
 		}
 	}
 
@@ -126,18 +264,25 @@ func Sign(args []string, a sigAlg) error {
 		a.UsageError()
 	}
 
-	err := a.LoadPriv(key)
+	err := loadPriv(a, key, inform, pass)
 	if err != nil {
 		return err
 	}
+	sig := new(bytes.Buffer)
+	err = a.Sign(in, sig)
+	if err != nil {
+		return err
+	}
+	alg, hash := a.Describe()
 
-	return a.Sign(in, out)
+	return util.WriteSig(out, sig.Bytes(), sigform, alg, hash)
 }
 
 func Verify(args []string, a sigAlg) error {
 	var in  *os.File = os.Stdin
 	var key *os.File
 	var sig *os.File
+	var inform string
 
 	for i := 0; i < len(args); i++ {
 		switch args[i] {
@@ -156,8 +301,12 @@ func Verify(args []string, a sigAlg) error {
 		case "--sig":
 			util.OpenFile(&sig, nil,
 			    util.GetArg(args, &i))
+		case "--inform":
+			inform = util.GetFormArg(args, &i)
 		default:
-			a.UsageError()
+			if a.Option("verify", args, &i) == false {
+				a.UsageError()
+			}
 		}
 	}
 
@@ -165,12 +314,17 @@ func Verify(args []string, a sigAlg) error {
 		a.UsageError()
 	}
 
-	err := a.LoadPub(key)
+	err := loadPub(a, key, inform)
+	if err != nil {
+		return err
+	}
+	alg, hash := a.Describe()
+	body, err := util.ReadSig(sig, alg, hash)
 	if err != nil {
 		return err
 	}
 
-	ok, err := a.Verify(sig, in)
+	ok, err := a.Verify(bytes.NewReader(body), in)
 	if err != nil {
 		return err
 	}
@@ -185,6 +339,142 @@ func Verify(args []string, a sigAlg) error {
 	return nil
 }
 
+func Recover(args []string, a sigAlg) error {
+	var in  *os.File = os.Stdin
+	var out *os.File = os.Stdout
+	var sig *os.File
+
+	rec, ok := a.(recoverer)
+	if ok == false {
+		a.UsageError()
+	}
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-i":
+			fallthrough
+		case "--in":
+			util.OpenFile(&in, os.Stdin,
+			    util.GetArg(args, &i))
+		case "-o":
+			fallthrough
+		case "--out":
+			util.CreateFile(&out, os.Stdout,
+			    util.GetArg(args, &i))
+		case "-s":
+			fallthrough
+		case "--sig":
+			util.OpenFile(&sig, nil,
+			    util.GetArg(args, &i))
+		default:
+			if a.Option("recover", args, &i) == false {
+				a.UsageError()
+			}
+		}
+	}
+
+	if sig == nil {
+		a.UsageError()
+	}
+	alg, hash := a.Describe()
+	body, err := util.ReadSig(sig, alg, hash)
+	if err != nil {
+		return err
+	}
+
+	return rec.Recover(bytes.NewReader(body), in, out)
+}
+
+func SigConv(args []string, a sigAlg) error {
+	var in  *os.File = os.Stdin
+	var out *os.File = os.Stdout
+	var sigform string
+
+	conv, ok := a.(converter)
+	if ok == false {
+		a.UsageError()
+	}
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-i":
+			fallthrough
+		case "--in":
+			util.OpenFile(&in, os.Stdin,
+			    util.GetArg(args, &i))
+		case "-o":
+			fallthrough
+		case "--out":
+			util.CreateFile(&out, os.Stdout,
+			    util.GetArg(args, &i))
+		case "--sig-format":
+			sigform = util.GetSigFormArg(args, &i)
+		default:
+			if a.Option("sigconv", args, &i) == false {
+				a.UsageError()
+			}
+		}
+	}
+
+	alg, hash := a.Describe()
+	body, err := util.ReadSig(in, alg, hash)
+	if err != nil {
+		return err
+	}
+	sig := new(bytes.Buffer)
+	err = conv.Convert(bytes.NewReader(body), sig)
+	if err != nil {
+		return err
+	}
+
+	return util.WriteSig(out, sig.Bytes(), sigform, alg, hash)
+}
+
+func Check(args []string, a sigAlg) error {
+	var in *os.File = os.Stdin
+	var pass string
+
+	chk, ok := a.(checker)
+	if ok == false {
+		a.UsageError()
+	}
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-i":
+			fallthrough
+		case "--in":
+			util.OpenKey(&in, os.Stdin,
+			    util.GetArg(args, &i))
+		case "--pass":
+			pass = util.GetArg(args, &i)
+		default:
+			if a.Option("check", args, &i) == false {
+				a.UsageError()
+			}
+		}
+	}
+
+	err := loadPriv(a, in, "", pass)
+	if err != nil {
+		return err
+	}
+
+	errs := chk.Check()
+	for _, err := range errs {
+		fmt.Fprintf(os.Stdout, "%v\n", err)
+	}
+	if len(errs) != 0 {
+		fmt.Fprintf(os.Stdout, "bad key\n")
+		os.Exit(1)
+	} else {
+		fmt.Fprintf(os.Stdout, "good key\n")
+		os.Exit(0)
+	}
+
+	return nil
+}
+
 func sigOp(args []string, a sigAlg) {
 	var err error
 
@@ -201,6 +491,12 @@ func sigOp(args []string, a sigAlg) {
 		err = Sign(args[2:], a)
 	case "verify":
 		err = Verify(args[2:], a)
+	case "recover":
+		err = Recover(args[2:], a)
+	case "check":
+		err = Check(args[2:], a)
+	case "sigconv":
+		err = SigConv(args[2:], a)
 	default:
 		a.UsageError()
 	}
@@ -219,8 +515,17 @@ func main() {
 	switch os.Args[1] {
 	case "ecdsa":
 		sigOp(os.Args[1:], ecdsa.New())
+	case "ed25519":
+		sigOp(os.Args[1:], ed25519.New())
+	case "jwk":
+		jwk.Command(os.Args[1:])
+	case "jwt":
+		jwt.Command(os.Args[1:], []jwt.Alg{rsa.New(), ecdsa.New(),
+		    ed25519.New()})
 	case "rsa":
 		sigOp(os.Args[1:], rsa.New())
+	case "schnorr":
+		sigOp(os.Args[1:], schnorr.New())
 	case "sha256":
 		sha256.Command(os.Args[1:])
 	case "version":
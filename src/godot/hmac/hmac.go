@@ -0,0 +1,45 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// The hmac module implements HMAC as defined in RFC 2104, with
+// SHA-256 as the underlying digest mechanism.
+
+package hmac
+
+import (
+	"godot/sha256"
+)
+
+const (
+	blockLen = 64 // bytes in a SHA-256 block
+)
+
+// SHA256() returns HMAC-SHA256(key, m).
+func SHA256(key, m []byte) ([]byte, error) {
+	var err error
+
+	// keys longer than a block are hashed first.
+	if len(key) > blockLen {
+		key, err = sha256.DigestBytes(key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	ipad := make([]byte, blockLen, blockLen + len(m))
+	opad := make([]byte, blockLen, blockLen + sha256.Len)
+	copy(ipad, key)
+	copy(opad, key)
+	for i := 0; i < blockLen; i++ {
+		ipad[i] ^= 0x36
+		opad[i] ^= 0x5c
+	}
+
+	// H(K ^ opad || H(K ^ ipad || m))
+	h, err := sha256.DigestBytes(append(ipad, m...))
+	if err != nil {
+		return nil, err
+	}
+
+	return sha256.DigestBytes(append(opad, h...))
+}
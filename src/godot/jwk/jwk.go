@@ -0,0 +1,220 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// The jwk module implements JSON Web Keys (RFC 7517, RFC 7518 and RFC
+// 8037) and their thumbprints (RFC 7638). The mapping between JWKs and
+// the keys of each algorithm is left to the algorithm itself.
+
+package jwk
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"godot/sha256"
+	"godot/util"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+)
+
+var (
+	ErrBadKey = errors.New("jwk: invalid key")
+)
+
+// A Key holds the members of a JWK used by godot. All values are
+// base64url-encoded, as per https://tools.ietf.org/rfc/rfc7518.txt,
+// sections 6.2 and 6.3, and https://tools.ietf.org/rfc/rfc8037.txt,
+// section 2.
+type Key struct {
+	Kty	string `json:"kty"`
+	Crv	string `json:"crv,omitempty"`
+	N	string `json:"n,omitempty"`
+	E	string `json:"e,omitempty"`
+	X	string `json:"x,omitempty"`
+	Y	string `json:"y,omitempty"`
+	D	string `json:"d,omitempty"`
+	P	string `json:"p,omitempty"`
+	Q	string `json:"q,omitempty"`
+	DP	string `json:"dp,omitempty"`
+	DQ	string `json:"dq,omitempty"`
+	QI	string `json:"qi,omitempty"`
+}
+
+// IsKey() tells whether body looks like a JWK.
+func IsKey(body []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("{"))
+}
+
+// Read() reads a JWK from r.
+func Read(r io.Reader) (*Key, error) {
+	var k = new(Key)
+
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(body, k)
+	if err != nil {
+		return nil, err
+	}
+	if k.Kty == "" {
+		return nil, ErrBadKey
+	}
+
+	return k, nil
+}
+
+// Write() writes a JWK to w, on a single line.
+func (k *Key) Write(w io.Writer) error {
+	body, err := json.Marshal(k)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(body, '\n'))
+
+	return err
+}
+
+// IsPrivate() tells whether k holds a private key.
+func (k *Key) IsPrivate() bool {
+	return k.D != ""
+}
+
+// EncodeBytes() base64url-encodes p, without padding.
+func EncodeBytes(p []byte) string {
+	return base64.RawURLEncoding.EncodeToString(p)
+}
+
+// DecodeBytes() decodes a base64url-encoded value of l bytes.
+func DecodeBytes(s string, l int) ([]byte, error) {
+	p, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(p) != l {
+		return nil, ErrBadKey
+	}
+
+	return p, nil
+}
+
+// EncodeInt() base64url-encodes x, in exactly l bytes if l is not
+// zero, and in as few bytes as possible otherwise.
+func EncodeInt(x *big.Int, l int) (string, error) {
+	if x.Sign() < 0 || (l != 0 && (x.BitLen() + 7) / 8 > l) {
+		return "", ErrBadKey
+	}
+	if l == 0 {
+		return EncodeBytes(x.Bytes()), nil
+	}
+
+	return EncodeBytes(x.FillBytes(make([]byte, l))), nil
+}
+
+// DecodeInt() decodes a base64url-encoded integer of exactly l bytes
+// if l is not zero, and of as few bytes as possible otherwise.
+func DecodeInt(s string, l int) (*big.Int, error) {
+	p, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(p) == 0 || (l != 0 && len(p) != l) ||
+	   (l == 0 && p[0] == 0) {
+		return nil, ErrBadKey
+	}
+
+	return new(big.Int).SetBytes(p), nil
+}
+
+// Thumbprint() computes the SHA-256 thumbprint of a JWK, as per
+// https://tools.ietf.org/rfc/rfc7638.txt, section 3. Only the required
+// members of the public key are hashed.
+func Thumbprint(k *Key) ([]byte, error) {
+	var m map[string]string
+
+	switch k.Kty {
+	case "RSA":
+		m = map[string]string{"kty": k.Kty, "n": k.N, "e": k.E}
+	case "EC":
+		m = map[string]string{"kty": k.Kty, "crv": k.Crv, "x": k.X,
+		    "y": k.Y}
+	case "OKP":
+		m = map[string]string{"kty": k.Kty, "crv": k.Crv, "x": k.X}
+	default:
+		return nil, errors.New("jwk: unsupported key type")
+	}
+	for _, v := range m {
+		if v == "" {
+			return nil, ErrBadKey
+		}
+	}
+	// json.Marshal() sorts the members of a map and adds no
+	// whitespace, as required by section 3.3.
+	body, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return sha256.DigestBytes(body)
+}
+
+func usageError() {
+	fmt.Fprintf(os.Stderr,
+`usage: godot jwk thumbprint [-i <file>] [-o <file>]
+
+Computes the SHA-256 thumbprint of a JSON Web Key as specified in
+RFC 7638, and writes it in base64url format.
+
+-i <file>	read the key from <file> instead of stdin
+-o <file>	write the thumbprint to <file> instead of stdout
+
+--{in,out} can be used instead of -{i,o}.
+`)
+	os.Exit(1)
+}
+
+// Command() is the entry point for command line operations.
+func Command(args []string) {
+	var in  *os.File = os.Stdin
+	var out *os.File = os.Stdout
+
+	// args[0] = "jwk", args[1] = "thumbprint"
+	if len(args) < 2 || args[1] != "thumbprint" {
+		usageError()
+	}
+
+	// parse options
+	for i := 2; i < len(args); i++ {
+		switch args[i] {
+		case "-i":
+			fallthrough
+		case "--in":
+			util.OpenFile(&in, os.Stdin,
+			    util.GetArg(args, &i))
+		case "-o":
+			fallthrough
+		case "--out":
+			util.CreateFile(&out, os.Stdout,
+			    util.GetArg(args, &i))
+		default:
+			usageError()
+		}
+	}
+
+	k, err := Read(in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	t, err := Thumbprint(k)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(out, "%s\n", EncodeBytes(t))
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package jwk
+
+import (
+	"strings"
+	"testing"
+)
+
+// The example key of https://tools.ietf.org/rfc/rfc7638.txt, section
+// 3.1, and its thumbprint.
+const rfc7638Key = `{"kty":"RSA",` +
+    `"n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbf` +
+    `AAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknj` +
+    `hMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65` +
+    `YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQ` +
+    `vRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lF` +
+    `d2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzK` +
+    `nqDKgw",` +
+    `"e":"AQAB","alg":"RS256","kid":"2011-04-29"}`
+
+const rfc7638Thumbprint = "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+
+func TestThumbprint(t *testing.T) {
+	k, err := Read(strings.NewReader(rfc7638Key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := Thumbprint(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if EncodeBytes(p) != rfc7638Thumbprint {
+		t.Errorf("got %s, want %s", EncodeBytes(p), rfc7638Thumbprint)
+	}
+	k.E = ""
+	_, err = Thumbprint(k)
+	if err != ErrBadKey {
+		t.Errorf("got %v, want %v", err, ErrBadKey)
+	}
+}
+
+func TestInt(t *testing.T) {
+	k, err := Read(strings.NewReader(rfc7638Key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := DecodeInt(k.N, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.BitLen() != 2048 {
+		t.Errorf("got %d bits, want 2048", n.BitLen())
+	}
+	s, err := EncodeInt(n, 0)
+	if err != nil || s != k.N {
+		t.Errorf("EncodeInt: round-trip failed")
+	}
+	_, err = DecodeInt(k.N, 255)
+	if err != ErrBadKey {
+		t.Errorf("got %v, want %v", err, ErrBadKey)
+	}
+	_, err = DecodeInt("AAEA", 0)
+	if err != ErrBadKey {
+		t.Errorf("got %v, want %v", err, ErrBadKey)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package jwt
+
+import (
+	"bytes"
+	"fmt"
+	"godot/pkcs8"
+	"godot/util"
+	"os"
+	"time"
+)
+
+func usageError() {
+	fmt.Fprintf(os.Stderr,
+`usage: godot jwt [command] [arguments]
+
+The supported commands are:
+
+godot jwt sign -k <file> [--pass <src>] [-a <alg>] [--claims <file>]
+    [-o <file>]
+
+	Creates a JSON Web Token in the JWS compact serialization. The
+	-k parameter must be specified, and <file> must point to an
+	RSA, ECDSA or Ed25519 private key, whose passphrase is read
+	from <src> as described for godot rsa pub. If -a is specified,
+	<alg> selects the JWS algorithm: PS256 or RS256 for RSA keys,
+	ES256 for P-256 keys, ES256K for secp256k1 keys, or EdDSA for
+	Ed25519 keys; otherwise PS256 is used for RSA keys, and the
+	only algorithm available for other keys. The claims are read
+	from <file> if --claims is specified, or from stdin otherwise,
+	and must be a JSON object; they are signed as they are. If -o
+	is specified, the token is written to <file> instead of
+	stdout.
+
+godot jwt verify -k <file> [-a <alg>] [--leeway <seconds>] [-i <file>]
+    [-o <file>]
+
+	Verifies a JSON Web Token. The -k parameter must be specified,
+	and <file> must point to a public key of one of the types
+	described for sign. The token must have been signed with the
+	JWS algorithm <alg> if -a is specified, or with the default
+	algorithm of the key otherwise, as described for sign; tokens
+	naming another algorithm are rejected. The exp, nbf and iat
+	claims are checked against the current time, allowing for a
+	clock skew of <seconds> if --leeway is specified. If -i is
+	specified, the token is read from <file> instead of stdin. If
+	-o is specified, the token's claims are written to <file>.
+
+--{alg,in,key,out} can be used instead of -{a,i,k,o}.
+`)
+	os.Exit(1)
+}
+
+// loadPriv() loads a private key with the first algorithm that accepts
+// it, decrypting it first if needed.
+func loadPriv(algs []Alg, f *os.File, src string) (Alg, error) {
+	body, err := pkcs8.DecryptPEM(util.ReadAll(f), func() []byte {
+		return util.ReadPass(src, false)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range algs {
+		if a.LoadPriv(bytes.NewReader(body)) == nil {
+			return a, nil
+		}
+	}
+
+	return nil, ErrBadKey
+}
+
+// loadPub() loads a public key with the first algorithm that accepts
+// it.
+func loadPub(algs []Alg, f *os.File) (Alg, error) {
+	body := util.ReadAll(f)
+	for _, a := range algs {
+		if a.LoadPub(bytes.NewReader(body)) == nil {
+			return a, nil
+		}
+	}
+
+	return nil, ErrBadKey
+}
+
+func sign(args []string, algs []Alg) error {
+	var in  *os.File = os.Stdin
+	var out *os.File = os.Stdout
+	var key *os.File
+	var pass, alg string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-a":
+			fallthrough
+		case "--alg":
+			alg = util.GetArg(args, &i)
+		case "--claims":
+			util.OpenFile(&in, os.Stdin,
+			    util.GetArg(args, &i))
+		case "-k":
+			fallthrough
+		case "--key":
+			util.OpenKey(&key, nil,
+			    util.GetArg(args, &i))
+		case "-o":
+			fallthrough
+		case "--out":
+			util.CreateFile(&out, os.Stdout,
+			    util.GetArg(args, &i))
+		case "--pass":
+			pass = util.GetArg(args, &i)
+		default:
+			usageError()
+		}
+	}
+
+	if key == nil {
+		usageError()
+	}
+
+	a, err := loadPriv(algs, key, pass)
+	if err != nil {
+		return err
+	}
+	if alg == "" {
+		alg = a.JWSAlg()
+	}
+	token, err := Sign(a, alg, util.ReadAll(in))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s\n", token)
+
+	return nil
+}
+
+func verify(args []string, algs []Alg) error {
+	var in  *os.File = os.Stdin
+	var out *os.File
+	var key *os.File
+	var leeway int
+	var alg string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-a":
+			fallthrough
+		case "--alg":
+			alg = util.GetArg(args, &i)
+		case "-i":
+			fallthrough
+		case "--in":
+			util.OpenFile(&in, os.Stdin,
+			    util.GetArg(args, &i))
+		case "-k":
+			fallthrough
+		case "--key":
+			util.OpenFile(&key, nil,
+			    util.GetArg(args, &i))
+		case "--leeway":
+			leeway = util.GetIntArg(args, &i)
+		case "-o":
+			fallthrough
+		case "--out":
+			util.CreateFile(&out, nil,
+			    util.GetArg(args, &i))
+		default:
+			usageError()
+		}
+	}
+
+	if key == nil || leeway < 0 {
+		usageError()
+	}
+
+	a, err := loadPub(algs, key)
+	if err != nil {
+		return err
+	}
+	if alg == "" {
+		alg = a.JWSAlg()
+	}
+	body, ok, err := Verify(a, alg, string(util.ReadAll(in)))
+	if err != nil {
+		return err
+	}
+	if ok == false {
+		fmt.Fprintf(os.Stdout, "bad token\n")
+		os.Exit(1)
+	}
+	err = Validate(body, time.Duration(leeway) * time.Second)
+	if err != nil {
+		return err
+	}
+	if out != nil {
+		out.Write(append(body, '\n'))
+	}
+	fmt.Fprintf(os.Stdout, "good token\n")
+
+	return nil
+}
+
+// Command() is the entry point for command line operations. algs are
+// the algorithms keys are tried with, in order.
+func Command(args []string, algs []Alg) {
+	var err error
+
+	// args[0] = "jwt"
+	if len(args) < 2 {
+		usageError()
+	}
+
+	switch args[1] {
+	case "sign":
+		err = sign(args[2:], algs)
+	case "verify":
+		err = verify(args[2:], algs)
+	default:
+		usageError()
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// The jwt module implements JSON Web Tokens (RFC 7519) in the JWS
+// compact serialization (RFC 7515). Signatures are delegated to the
+// signature algorithms themselves.
+
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBadKey     = errors.New("jwt: unsupported key")
+	ErrBadToken   = errors.New("jwt: invalid token")
+	ErrExpired    = errors.New("jwt: token expired")
+	ErrNotYet     = errors.New("jwt: token not yet valid")
+	ErrFuture     = errors.New("jwt: token issued in the future")
+	ErrBadClaims  = errors.New("jwt: invalid claims")
+	ErrAlg        = errors.New("jwt: unexpected algorithm")
+)
+
+// An Alg is a signature algorithm able to produce JWS signatures.
+// JWSAlg() returns the default JWS algorithm of the loaded key, and
+// SetJWS() configures the algorithm to produce and verify signatures
+// as mandated for the JWS algorithm alg.
+type Alg interface {
+	LoadPriv(r io.Reader) error
+	LoadPub(r io.Reader) error
+	Sign(m io.Reader, w io.Writer) error
+	Verify(t, m io.Reader) (bool, error)
+	JWSAlg() string
+	SetJWS(alg string) error
+}
+
+// As per https://tools.ietf.org/rfc/rfc7515.txt, section 4.1.
+type header struct {
+	Alg	string   `json:"alg"`
+	Typ	string   `json:"typ,omitempty"`
+	Crit	[]string `json:"crit,omitempty"`
+}
+
+// A numericDate is a number of seconds since the epoch, possibly with
+// a fraction. As per https://tools.ietf.org/rfc/rfc7519.txt, section
+// 2, it must be a JSON number; strings and null are refused.
+type numericDate struct {
+	set	bool
+	v	float64
+}
+
+// The registered claims checked by Validate(), as per
+// https://tools.ietf.org/rfc/rfc7519.txt, section 4.1.
+type claims struct {
+	Exp	numericDate `json:"exp"`
+	Nbf	numericDate `json:"nbf"`
+	Iat	numericDate `json:"iat"`
+}
+
+func encode(p []byte) string {
+	return base64.RawURLEncoding.EncodeToString(p)
+}
+
+func decode(s string) ([]byte, error) {
+	p, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrBadToken
+	}
+
+	return p, nil
+}
+
+// checkClaims() ensures body is a JSON object.
+func checkClaims(body []byte) error {
+	var m map[string]interface{}
+
+	err := json.Unmarshal(body, &m)
+	if err != nil || m == nil {
+		return ErrBadClaims
+	}
+
+	return nil
+}
+
+// Sign() signs the claims in body with a, using the JWS algorithm alg,
+// and returns the resulting token.
+func Sign(a Alg, alg string, body []byte) (string, error) {
+	var h header
+
+	body = bytes.TrimSpace(body)
+	err := checkClaims(body)
+	if err != nil {
+		return "", err
+	}
+	err = a.SetJWS(alg)
+	if err != nil {
+		return "", err
+	}
+	h.Alg = alg
+	h.Typ = "JWT"
+	hdr, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	in := encode(hdr) + "." + encode(body)
+	sig := new(bytes.Buffer)
+	err = a.Sign(strings.NewReader(in), sig)
+	if err != nil {
+		return "", err
+	}
+
+	return in + "." + encode(sig.Bytes()), nil
+}
+
+// Verify() verifies the signature of a token with a, returning the
+// token's claims if the signature is valid. The JWS algorithm of the
+// token must be alg, which must be supported by the loaded key; the
+// token's header is not trusted to choose it.
+func Verify(a Alg, alg, token string) ([]byte, bool, error) {
+	var h header
+
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return nil, false, ErrBadToken
+	}
+	hdr, err := decode(parts[0])
+	if err != nil {
+		return nil, false, err
+	}
+	body, err := decode(parts[1])
+	if err != nil {
+		return nil, false, err
+	}
+	sig, err := decode(parts[2])
+	if err != nil {
+		return nil, false, err
+	}
+	err = json.Unmarshal(hdr, &h)
+	if err != nil {
+		return nil, false, ErrBadToken
+	}
+	// We understand no extensions (RFC 7515, section 4.1.11).
+	if h.Alg == "" || len(h.Crit) != 0 {
+		return nil, false, ErrBadToken
+	}
+	if h.Alg != alg {
+		return nil, false, ErrAlg
+	}
+	err = a.SetJWS(alg)
+	if err != nil {
+		return nil, false, err
+	}
+	ok, err := a.Verify(bytes.NewReader(sig),
+	    strings.NewReader(parts[0] + "." + parts[1]))
+	if err != nil || ok == false {
+		return nil, false, err
+	}
+	err = checkClaims(body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return body, true, nil
+}
+
+// UnmarshalJSON() parses a NumericDate. p is known to be valid JSON,
+// so it is a number if it starts with a digit or a minus sign.
+func (d *numericDate) UnmarshalJSON(p []byte) error {
+	if len(p) == 0 || (p[0] != '-' && (p[0] < '0' || p[0] > '9')) {
+		return ErrBadClaims
+	}
+	v, err := strconv.ParseFloat(string(p), 64)
+	if err != nil {
+		return ErrBadClaims
+	}
+	d.set = true
+	d.v = v
+
+	return nil
+}
+
+// Validate() checks the exp, nbf and iat claims against the current
+// time, allowing for a clock skew of leeway.
+func Validate(body []byte, leeway time.Duration) error {
+	var c claims
+
+	err := json.Unmarshal(body, &c)
+	if err != nil {
+		return ErrBadClaims
+	}
+	now := float64(time.Now().Unix())
+	skew := leeway.Seconds()
+	if c.Exp.set && now >= c.Exp.v + skew {
+		return ErrExpired
+	}
+	if c.Nbf.set && now + skew < c.Nbf.v {
+		return ErrNotYet
+	}
+	if c.Iat.set && now + skew < c.Iat.v {
+		return ErrFuture
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package jwt
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// A stub algorithm, whose "signature" is the signed input itself. It
+// accepts the JWS algorithms XS256 and XS512.
+type stub struct {
+	alg string
+}
+
+func (s *stub) LoadPriv(r io.Reader) error {
+	return nil
+}
+
+func (s *stub) LoadPub(r io.Reader) error {
+	return nil
+}
+
+func (s *stub) Sign(m io.Reader, w io.Writer) error {
+	p, err := ioutil.ReadAll(m)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append([]byte(s.alg), p...))
+	return err
+}
+
+func (s *stub) Verify(t, m io.Reader) (bool, error) {
+	sig, err := ioutil.ReadAll(t)
+	if err != nil {
+		return false, err
+	}
+	p, err := ioutil.ReadAll(m)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(sig, append([]byte(s.alg), p...)), nil
+}
+
+func (s *stub) JWSAlg() string {
+	return "XS256"
+}
+
+func (s *stub) SetJWS(alg string) error {
+	if alg != "XS256" && alg != "XS512" {
+		return errors.New("unsupported algorithm")
+	}
+	s.alg = alg
+	return nil
+}
+
+func TestVerifyAlg(t *testing.T) {
+	token, err := Sign(new(stub), "XS512", []byte(`{"sub":"x"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, ok, err := Verify(new(stub), "XS512", token)
+	if err != nil || ok == false || string(body) != `{"sub":"x"}` {
+		t.Errorf("XS512: got %q, %v, %v", body, ok, err)
+	}
+	// The key supports XS512, but the caller expects XS256.
+	_, ok, err = Verify(new(stub), "XS256", token)
+	if err != ErrAlg || ok {
+		t.Errorf("XS256: got %v, %v, want %v", ok, err, ErrAlg)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Now().Unix()
+	past := strconv.FormatInt(now - 3600, 10)
+	future := strconv.FormatInt(now + 3600, 10)
+	vectors := []struct {
+		body string
+		err  error
+	}{
+		{`{}`, nil},
+		{`{"exp":` + future + `,"nbf":` + past + `,"iat":` + past + `}`,
+		    nil},
+		{`{"exp":` + future + `.5}`, nil},
+		{`{"exp":` + past + `}`, ErrExpired},
+		{`{"nbf":` + future + `}`, ErrNotYet},
+		{`{"iat":` + future + `}`, ErrFuture},
+		// NumericDate values must be JSON numbers.
+		{`{"exp":"` + future + `"}`, ErrBadClaims},
+		{`{"nbf":"` + past + `"}`, ErrBadClaims},
+		{`{"iat":null}`, ErrBadClaims},
+		{`{"exp":true}`, ErrBadClaims},
+		{`[]`, ErrBadClaims},
+	}
+
+	for i, v := range vectors {
+		err := Validate([]byte(v.body), 0)
+		if err != v.err {
+			t.Errorf("vector %d: got %v, want %v", i, err, v.err)
+		}
+	}
+
+	// Leeway.
+	err := Validate([]byte(`{"exp":` + past + `}`), 2 * time.Hour)
+	if err != nil {
+		t.Errorf("leeway: got %v", err)
+	}
+}
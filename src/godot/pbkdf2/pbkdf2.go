@@ -0,0 +1,48 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// The pbkdf2 module implements PBKDF2 as defined in RFC 8018, section
+// 5.2, with HMAC-SHA256 as the underlying pseudorandom function.
+
+package pbkdf2
+
+import (
+	"encoding/binary"
+	"errors"
+	"godot/hmac"
+	"godot/sha256"
+)
+
+// SHA256() derives a key of l bytes from a password p and a salt s,
+// using c iterations.
+func SHA256(p, s []byte, c, l int) ([]byte, error) {
+	if c < 1 || l < 1 {
+		return nil, errors.New("invalid pbkdf2 parameters")
+	}
+	dk := make([]byte, 0, l + sha256.Len)
+
+	// T_i = U_1 ^ U_2 ^ ... ^ U_c, with U_1 = PRF(p, s || INT(i))
+	// and U_j = PRF(p, U_{j-1}).
+	for i := uint32(1); len(dk) < l; i++ {
+		var be [4]byte
+		binary.BigEndian.PutUint32(be[:], i)
+		u, err := hmac.SHA256(p, append(append([]byte{}, s...), be[:]...))
+		if err != nil {
+			return nil, err
+		}
+		t := append([]byte{}, u...)
+		for j := 1; j < c; j++ {
+			u, err = hmac.SHA256(p, u)
+			if err != nil {
+				return nil, err
+			}
+			for k := range t {
+				t[k] ^= u[k]
+			}
+		}
+		dk = append(dk, t...)
+	}
+
+	return dk[:l], nil
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// pbes2.go implements encrypted PKCS#8 private keys, using PBES2 (RFC
+// 8018, section 6.2) with PBKDF2-HMAC-SHA256 and AES-CBC. These are
+// the parameters used by openssl genpkey -aes256.
+
+package pkcs8
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"godot/pbkdf2"
+	"godot/rand"
+	"godot/util"
+	"io"
+)
+
+var (
+	ErrDecrypt     = errors.New("pkcs8: decryption failed")
+	ErrUnsupported = errors.New("pkcs8: unsupported encryption")
+)
+
+// The PEM type of an encrypted PKCS#8 private key.
+const EncryptedPemType = "ENCRYPTED PRIVATE KEY"
+
+// Iterations of PBKDF2 used when encrypting a key.
+const Iterations = 100000
+
+// The largest iteration count accepted when decrypting a key, so that
+// a crafted key cannot stall us for hours.
+const MaxIterations = 10000000
+
+// As per https://tools.ietf.org/rfc/rfc8018.txt, appendices A.2, A.4,
+// B.1.2 and B.2.5.
+var (
+	oidPBES2      asn1.ObjectIdentifier = []int{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2     asn1.ObjectIdentifier = []int{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACSHA256 asn1.ObjectIdentifier = []int{1, 2, 840, 113549, 2, 9}
+	oidAES128CBC  asn1.ObjectIdentifier = []int{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  asn1.ObjectIdentifier = []int{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  asn1.ObjectIdentifier = []int{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+// As per https://tools.ietf.org/rfc/rfc5208.txt, section 6.
+type EncryptedPrivateKeyInfo struct {
+	Algorithm	AlgorithmIdentifier
+	EncryptedData	[]byte
+}
+
+// As per https://tools.ietf.org/rfc/rfc8018.txt, appendix A.4.
+type pbes2Params struct {
+	KeyDerivationFunc	AlgorithmIdentifier
+	EncryptionScheme	AlgorithmIdentifier
+}
+
+// As per https://tools.ietf.org/rfc/rfc8018.txt, appendix A.2. An
+// absent PRF means HMAC-SHA1, which is not supported.
+type pbkdf2Params struct {
+	Salt		[]byte
+	IterationCount	int
+	KeyLength	int `asn1:"optional"`
+	PRF		AlgorithmIdentifier `asn1:"optional"`
+}
+
+// aesKeyLen() returns the key length of an AES-CBC scheme.
+func aesKeyLen(oid asn1.ObjectIdentifier) int {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return 16
+	case oid.Equal(oidAES192CBC):
+		return 24
+	case oid.Equal(oidAES256CBC):
+		return 32
+	default:
+		return 0
+	}
+}
+
+// Encrypt() encrypts a DER-encoded PrivateKeyInfo with a passphrase,
+// returning a DER-encoded EncryptedPrivateKeyInfo.
+func Encrypt(der, pass []byte) ([]byte, error) {
+	var kdf pbkdf2Params
+	var scheme pbes2Params
+	var k EncryptedPrivateKeyInfo
+
+	salt, err := rand.Bytes(16)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := rand.Bytes(aes.BlockSize)
+	if err != nil {
+		return nil, err
+	}
+	key, err := pbkdf2.SHA256(pass, salt, Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	// pad as per RFC 8018, section 6.1.1, step 4.
+	n := aes.BlockSize - len(der) % aes.BlockSize
+	data := append(append([]byte{}, der...), bytes.Repeat([]byte{byte(n)},
+	    n)...)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(data, data)
+
+	kdf.Salt = salt
+	kdf.IterationCount = Iterations
+	kdf.PRF.Algorithm = oidHMACSHA256
+	kdf.PRF.Parameters = asn1.NullRawValue
+	scheme.KeyDerivationFunc.Algorithm = oidPBKDF2
+	scheme.KeyDerivationFunc.Parameters.FullBytes, err = asn1.Marshal(kdf)
+	if err != nil {
+		return nil, err
+	}
+	scheme.EncryptionScheme.Algorithm = oidAES256CBC
+	scheme.EncryptionScheme.Parameters.FullBytes, err = asn1.Marshal(iv)
+	if err != nil {
+		return nil, err
+	}
+	k.Algorithm.Algorithm = oidPBES2
+	k.Algorithm.Parameters.FullBytes, err = asn1.Marshal(scheme)
+	if err != nil {
+		return nil, err
+	}
+	k.EncryptedData = data
+
+	return asn1.Marshal(k)
+}
+
+// Decrypt() decrypts a DER-encoded EncryptedPrivateKeyInfo with a
+// passphrase, returning a DER-encoded PrivateKeyInfo.
+func Decrypt(der, pass []byte) ([]byte, error) {
+	var k EncryptedPrivateKeyInfo
+	var scheme pbes2Params
+	var kdf pbkdf2Params
+	var iv []byte
+
+	_, err := asn1.Unmarshal(der, &k)
+	if err != nil {
+		return nil, err
+	}
+	if oidPBES2.Equal(k.Algorithm.Algorithm) == false {
+		return nil, ErrUnsupported
+	}
+	_, err = asn1.Unmarshal(k.Algorithm.Parameters.FullBytes, &scheme)
+	if err != nil {
+		return nil, err
+	}
+	if oidPBKDF2.Equal(scheme.KeyDerivationFunc.Algorithm) == false {
+		return nil, ErrUnsupported
+	}
+	_, err = asn1.Unmarshal(scheme.KeyDerivationFunc.Parameters.FullBytes,
+	    &kdf)
+	if err != nil {
+		return nil, err
+	}
+	if oidHMACSHA256.Equal(kdf.PRF.Algorithm) == false {
+		return nil, ErrUnsupported
+	}
+	l := aesKeyLen(scheme.EncryptionScheme.Algorithm)
+	if l == 0 {
+		return nil, ErrUnsupported
+	}
+	if (kdf.KeyLength != 0 && kdf.KeyLength != l) ||
+	   kdf.IterationCount <= 0 || kdf.IterationCount > MaxIterations {
+		return nil, ErrDecrypt
+	}
+	_, err = asn1.Unmarshal(scheme.EncryptionScheme.Parameters.FullBytes,
+	    &iv)
+	if err != nil {
+		return nil, err
+	}
+	data := append([]byte{}, k.EncryptedData...)
+	if len(iv) != aes.BlockSize || len(data) == 0 ||
+	   len(data) % aes.BlockSize != 0 {
+		return nil, ErrDecrypt
+	}
+
+	key, err := pbkdf2.SHA256(pass, kdf.Salt, kdf.IterationCount, l)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(data, data)
+
+	// A wrong passphrase is usually detected by bogus padding.
+	n := int(data[len(data) - 1])
+	if n < 1 || n > aes.BlockSize ||
+	   bytes.Equal(data[len(data) - n:],
+	   bytes.Repeat([]byte{byte(n)}, n)) == false {
+		return nil, ErrDecrypt
+	}
+
+	return data[:len(data) - n], nil
+}
+
+// EncryptPEM() encrypts a PEM-encoded PKCS#8 private key with a
+// passphrase, and writes the result to w in PEM format.
+func EncryptPEM(body, pass []byte, w io.Writer) error {
+	blob, _ := pem.Decode(body)
+	if blob == nil || blob.Type != PemType {
+		return errors.New("pkcs8: only PKCS#8 keys can be encrypted")
+	}
+	der, err := Encrypt(blob.Bytes, pass)
+	if err != nil {
+		return err
+	}
+
+	return pem.Encode(w, &pem.Block{Type: EncryptedPemType, Bytes: der})
+}
+
+// DecryptPEM() decrypts a PEM- or DER-encoded private key, returning
+// it in PEM format. The passphrase is obtained from pass, which is only
+// called if the key is indeed encrypted; otherwise, body is returned as
+// is.
+func DecryptPEM(body []byte, pass func() []byte) ([]byte, error) {
+	der, t, ok := util.Unarmor(body)
+	if ok && t == "" {
+		t = TypeOf(der, "")
+	}
+	if ok == false || t != EncryptedPemType {
+		return body, nil
+	}
+	der, err := Decrypt(der, pass())
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: PemType, Bytes: der}), nil
+}
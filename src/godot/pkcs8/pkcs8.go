@@ -0,0 +1,90 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// The pkcs8 module exports functions that allow PKCS#8 private keys
+// to be read and written. It only deals with the PrivateKeyInfo
+// wrapper; the algorithm-specific private key inside it is left to the
+// caller.
+
+package pkcs8
+
+import (
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+)
+
+var (
+	ErrBadKey = errors.New("pkcs8: invalid key")
+)
+
+// The PEM type of a PKCS#8 private key.
+const PemType = "PRIVATE KEY"
+
+// As per https://tools.ietf.org/rfc/rfc5280.txt, section 4.1.1.2.
+type AlgorithmIdentifier struct {
+	Algorithm	asn1.ObjectIdentifier
+	Parameters	asn1.RawValue `asn1:"optional"`
+}
+
+// As per https://tools.ietf.org/rfc/rfc5208.txt, section 5.
+type PrivateKeyInfo struct {
+	Version		*big.Int
+	Algorithm	AlgorithmIdentifier
+	PrivateKey	[]byte // the DER-encoded private key
+}
+
+// Write() wraps a DER-encoded private key of algorithm alg in a
+// PrivateKeyInfo, and writes it in PEM format.
+func Write(alg AlgorithmIdentifier, key []byte, w io.Writer) error {
+	var k = new(PrivateKeyInfo)
+	var blob = new(pem.Block)
+	var err error
+
+	k.Version = big.NewInt(0)
+	k.Algorithm = alg
+	k.PrivateKey = key
+	blob.Type = PemType
+	blob.Bytes, err = asn1.Marshal(*k)
+	if err != nil {
+		return err
+	}
+
+	return pem.Encode(w, blob)
+}
+
+// Unmarshal() parses a DER-encoded PrivateKeyInfo.
+func Unmarshal(der []byte) (*PrivateKeyInfo, error) {
+	var k = new(PrivateKeyInfo)
+
+	rest, err := asn1.Unmarshal(der, k)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 || k.Version == nil || k.Version.Sign() != 0 {
+		return nil, ErrBadKey
+	}
+
+	return k, nil
+}
+
+// TypeOf() returns the PEM type of a DER-encoded private key: PemType
+// for a PrivateKeyInfo, EncryptedPemType for an EncryptedPrivateKeyInfo,
+// or t, the PEM type of the algorithm's own form, otherwise.
+func TypeOf(der []byte, t string) string {
+	var e EncryptedPrivateKeyInfo
+
+	_, err := Unmarshal(der)
+	if err == nil {
+		return PemType
+	}
+	rest, err := asn1.Unmarshal(der, &e)
+	if err == nil && len(rest) == 0 {
+		return EncryptedPemType
+	}
+
+	return t
+}
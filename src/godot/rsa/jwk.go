@@ -0,0 +1,106 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// This file maps RSA keys to and from JSON Web Keys, as specified in
+// RFC 7518, section 6.3.
+
+package rsa
+
+import (
+	"errors"
+	"godot/jwk"
+	"godot/rsa/pkcs1"
+	"math/big"
+)
+
+// toJWK() converts the private key into a JWK. If pub is true, only
+// the public members are set.
+func (k *rsa) toJWK(pub bool) (*jwk.Key, error) {
+	var j = new(jwk.Key)
+	var err error
+
+	key := k.pkcs1
+	j.Kty = "RSA"
+	fields := []struct {
+		v *string
+		x *big.Int
+	} {
+		{ &j.N, key.Modulus },
+		{ &j.E, key.PublicExponent },
+		{ &j.D, key.PrivateExponent },
+		{ &j.P, key.Prime1 },
+		{ &j.Q, key.Prime2 },
+		{ &j.DP, key.Exponent1 },
+		{ &j.DQ, key.Exponent2 },
+		{ &j.QI, key.Coefficient },
+	}
+	if pub {
+		fields = fields[:2]
+	}
+	for _, f := range fields {
+		*f.v, err = jwk.EncodeInt(f.x, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return j, nil
+}
+
+// pubFromJWK() converts the public members of a JWK into a public key.
+func pubFromJWK(j *jwk.Key) (*pkcs1.PublicKey, error) {
+	var pub = new(pkcs1.PublicKey)
+	var err error
+
+	if j.Kty != "RSA" {
+		return nil, errors.New("not an RSA key")
+	}
+	pub.Modulus, err = jwk.DecodeInt(j.N, 0)
+	if err != nil {
+		return nil, err
+	}
+	pub.PublicExponent, err = jwk.DecodeInt(j.E, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return pub, nil
+}
+
+// privFromJWK() converts a JWK into a private key. All of the CRT
+// members must be present.
+func privFromJWK(j *jwk.Key) (*pkcs1.PrivateKey, error) {
+	var key = new(pkcs1.PrivateKey)
+	var err error
+
+	pub, err := pubFromJWK(j)
+	if err != nil {
+		return nil, err
+	}
+	if j.IsPrivate() == false {
+		return nil, errors.New("not a private key")
+	}
+	key.Version = big.NewInt(0)
+	key.Modulus = pub.Modulus
+	key.PublicExponent = pub.PublicExponent
+	fields := []struct {
+		x **big.Int
+		v string
+	} {
+		{ &key.PrivateExponent, j.D },
+		{ &key.Prime1, j.P },
+		{ &key.Prime2, j.Q },
+		{ &key.Exponent1, j.DP },
+		{ &key.Exponent2, j.DQ },
+		{ &key.Coefficient, j.QI },
+	}
+	for _, f := range fields {
+		*f.x, err = jwk.DecodeInt(f.v, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
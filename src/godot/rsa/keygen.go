@@ -0,0 +1,156 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// This file implements RSA key generation as specified in FIPS 186-5,
+// Appendix A.1.3 (generation of random probable primes).
+
+package rsa
+
+import (
+	"bytes"
+	"errors"
+	"godot/rand"
+	"godot/rsa/pkcs1"
+	"math/big"
+)
+
+// randOdd() returns a random odd integer of exactly l bits.
+func randOdd(l int) (*big.Int, error) {
+	b, err := rand.Bytes((l + 7) / 8)
+	if err != nil {
+		return nil, err
+	}
+	x := new(big.Int).SetBytes(b)
+	x.SetBit(x, 0, 1)
+	for i := l; i < len(b) * 8; i++ {
+		x.SetBit(x, i, 0)
+	}
+	x.SetBit(x, l - 1, 1)
+
+	return x, nil
+}
+
+// mrRounds() returns the number of Miller-Rabin rounds to be applied
+// to a candidate l-bit prime, from Table B.1 of FIPS 186-5. The error
+// probabilities are 2^-112, 2^-128 and 2^-144 for 1024, 1536 and
+// 2048-bit primes; larger primes keep the rounds of the latter.
+func mrRounds(l int) int {
+	if l <= 1024 {
+		return 5
+	}
+
+	return 4
+}
+
+// genPrime() implements steps 4 and 5 of Appendix A.1.3: it returns an
+// l-bit probable prime x with x >= sqrt(2)*2^(l-1) and gcd(x-1,e) = 1.
+// If p is not nil, x is the second prime q, and |x-p| must also be
+// greater than 2^(l-100). At most 5*l candidates are tried for p, and
+// 10*l for q.
+func genPrime(l int, e, p *big.Int) (*big.Int, error) {
+	one := big.NewInt(1)
+	minDiff := new(big.Int).Lsh(one, uint(l - 100))
+	tries := 5 * l
+	if p != nil {
+		tries = 10 * l
+	}
+
+	for i := 0; i < tries; i++ {
+		x, err := randOdd(l)
+		if err != nil {
+			return nil, err
+		}
+		// x >= sqrt(2)*2^(l-1) if and only if x^2 >= 2^(2l-1).
+		if new(big.Int).Mul(x, x).BitLen() != 2 * l {
+			continue
+		}
+		if p != nil {
+			d := new(big.Int).Sub(x, p)
+			if d.Abs(d).Cmp(minDiff) != 1 {
+				continue
+			}
+		}
+		xMinus := new(big.Int).Sub(x, one)
+		if new(big.Int).GCD(nil, nil, xMinus, e).Cmp(one) != 0 {
+			continue
+		}
+		if x.ProbablyPrime(mrRounds(l)) {
+			return x, nil
+		}
+	}
+
+	return nil, errors.New("failed to generate prime")
+}
+
+// genKey() generates an l-bit private key with public exponent e.
+func genKey(l int, e *big.Int) (*pkcs1.PrivateKey, error) {
+	one := big.NewInt(1)
+	minD := new(big.Int).Lsh(one, uint(l / 2))
+
+	for {
+		p, err := genPrime(l / 2, e, nil)
+		if err != nil {
+			return nil, err
+		}
+		q, err := genPrime(l / 2, e, p)
+		if err != nil {
+			return nil, err
+		}
+		n := new(big.Int).Mul(p, q)
+		if n.BitLen() != l {
+			return nil, errors.New("modulus of wrong length")
+		}
+
+		// d = e^-1 mod lambda(n), lambda(n) = lcm(p-1, q-1)
+		pMinus := new(big.Int).Sub(p, one)
+		qMinus := new(big.Int).Sub(q, one)
+		gcd := new(big.Int).GCD(nil, nil, pMinus, qMinus)
+		lambda := new(big.Int).Mul(pMinus, qMinus)
+		lambda.Div(lambda, gcd)
+		d := new(big.Int).ModInverse(e, lambda)
+		if d == nil {
+			return nil, errors.New("e not invertible")
+		}
+		// d must be greater than 2^(l/2); try again otherwise.
+		if d.Cmp(minD) != 1 {
+			continue
+		}
+
+		key := new(pkcs1.PrivateKey)
+		key.Version = big.NewInt(0)
+		key.Prime1 = p
+		key.Prime2 = q
+		key.Modulus = n
+		key.PublicExponent = e
+		key.PrivateExponent = d
+		key.Exponent1 = new(big.Int).Mod(d, pMinus)
+		key.Exponent2 = new(big.Int).Mod(d, qMinus)
+		key.Coefficient = new(big.Int).ModInverse(q, p)
+
+		return key, nil
+	}
+}
+
+// pairwiseTest() signs and verifies a test message with a newly
+// generated key, as required by FIPS 140-3.
+func (k *rsa) pairwiseTest() error {
+	msg := []byte("godot pairwise consistency test")
+	pub := new(pkcs1.PublicKey)
+	pub.Modulus = k.pkcs1.Modulus
+	pub.PublicExponent = k.pkcs1.PublicExponent
+	t := &rsa{pkcs1: k.pkcs1, x509: pub, padding: "pss"}
+	sig := new(bytes.Buffer)
+	err := t.Sign(bytes.NewReader(msg), sig)
+	if err != nil {
+		return err
+	}
+	ok, err := t.Verify(sig, bytes.NewReader(msg))
+	if err != nil {
+		return err
+	} else if ok == false {
+		return errors.New("pairwise consistency test failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package rsa
+
+import (
+	"math/big"
+	"testing"
+)
+
+// TestGenKey() checks a generated key against the requirements of FIPS
+// 186-5, Appendix A.1.1 and A.1.3.
+func TestGenKey(t *testing.T) {
+	const l = 2048
+	one := big.NewInt(1)
+	e := big.NewInt(65537)
+	k, err := genKey(l, e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, q, n, d := k.Prime1, k.Prime2, k.Modulus, k.PrivateExponent
+
+	if n.BitLen() != l || p.BitLen() != l / 2 || q.BitLen() != l / 2 {
+		t.Errorf("bit lengths: n %d, p %d, q %d", n.BitLen(),
+		    p.BitLen(), q.BitLen())
+	}
+	if new(big.Int).Mul(p, q).Cmp(n) != 0 {
+		t.Errorf("n != p*q")
+	}
+	diff := new(big.Int).Sub(p, q)
+	if diff.Abs(diff).Cmp(new(big.Int).Lsh(one, l / 2 - 100)) != 1 {
+		t.Errorf("|p-q| <= 2^(nlen/2-100)")
+	}
+
+	// 2^(nlen/2) < d < lcm(p-1, q-1), and e*d = 1 mod lcm(p-1, q-1).
+	pMinus := new(big.Int).Sub(p, one)
+	qMinus := new(big.Int).Sub(q, one)
+	lambda := new(big.Int).Mul(pMinus, qMinus)
+	lambda.Div(lambda, new(big.Int).GCD(nil, nil, pMinus, qMinus))
+	if d.Cmp(new(big.Int).Lsh(one, l / 2)) != 1 {
+		t.Errorf("d <= 2^(nlen/2)")
+	}
+	if d.Cmp(lambda) != -1 {
+		t.Errorf("d >= lcm(p-1, q-1)")
+	}
+	ed := new(big.Int).Mul(e, d)
+	if ed.Mod(ed, lambda).Cmp(one) != 0 {
+		t.Errorf("e*d != 1 mod lcm(p-1, q-1)")
+	}
+
+	// The CRT parameters.
+	if k.Exponent1.Cmp(new(big.Int).Mod(d, pMinus)) != 0 ||
+	   k.Exponent2.Cmp(new(big.Int).Mod(d, qMinus)) != 0 {
+		t.Errorf("bad CRT exponents")
+	}
+	qInv := new(big.Int).Mul(k.Coefficient, q)
+	if qInv.Mod(qInv, p).Cmp(one) != 0 {
+		t.Errorf("bad CRT coefficient")
+	}
+}
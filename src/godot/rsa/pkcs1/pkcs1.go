@@ -3,7 +3,8 @@
 // that can be found in the LICENSE file.
 //
 // The pkcs1 module exports functions that allow RSA PKCS1
-// private keys to be read and written.
+// private keys to be read and written, either on their own or wrapped
+// in PKCS#8.
 
 package pkcs1
 
@@ -11,11 +12,16 @@ import (
 	"encoding/asn1"
 	"encoding/pem"
 	"errors"
+	"godot/pkcs8"
+	"godot/util"
 	"io"
 	"io/ioutil"
 	"math/big"
 )
 
+// As per https://tools.ietf.org/rfc/rfc3279.txt, 2.3.1
+var OID asn1.ObjectIdentifier = []int{1, 2, 840, 113549, 1, 1, 1}
+
 // As per https://www.ietf.org/rfc/rfc3447.txt, A.1.2
 type PrivateKey struct {
 	Version		*big.Int
@@ -49,7 +55,23 @@ func Write(rsa *PrivateKey, w io.Writer) error {
 	return pem.Encode(w, blob)
 }
 
-// Read() reads a PKCS1 RSA private key in PEM format.
+// WritePKCS8() writes a PKCS1 RSA private key wrapped in PKCS#8, in
+// PEM format.
+func WritePKCS8(rsa *PrivateKey, w io.Writer) error {
+	var alg pkcs8.AlgorithmIdentifier
+
+	der, err := asn1.Marshal(*rsa)
+	if err != nil {
+		return err
+	}
+	alg.Algorithm = OID
+	alg.Parameters = asn1.NullRawValue
+
+	return pkcs8.Write(alg, der, w)
+}
+
+// Read() reads a PKCS1 RSA private key in PEM or DER format. The key
+// may be wrapped in PKCS#8.
 func Read(r io.Reader) (*PrivateKey, error) {
 	var rsa = new (PrivateKey)
 
@@ -57,17 +79,90 @@ func Read(r io.Reader) (*PrivateKey, error) {
 	if err != nil {
 		return nil, err
 	}
-	blob, _ := pem.Decode(body)
-	if blob == nil {
+	der, t, ok := util.Unarmor(body)
+	if ok == false {
 		return nil, errors.New("pem decode error")
 	}
-	if blob.Type != "RSA PRIVATE KEY" || blob.Bytes == nil {
+	if t == "" {
+		t = pkcs8.TypeOf(der, "RSA PRIVATE KEY")
+	}
+	switch {
+	case der == nil:
+		return nil, errors.New("invalid pem")
+	case t == "RSA PRIVATE KEY":
+	case t == pkcs8.PemType:
+		k, err := pkcs8.Unmarshal(der)
+		if err != nil {
+			return nil, err
+		}
+		if OID.Equal(k.Algorithm.Algorithm) == false {
+			return nil, errors.New("not an RSA key")
+		}
+		der = k.PrivateKey
+	default:
 		return nil, errors.New("invalid pem")
 	}
-	_, err = asn1.Unmarshal(blob.Bytes, rsa)
+	_, err = asn1.Unmarshal(der, rsa)
 	if err != nil {
 		return nil, err
 	}
 
 	return rsa, nil
 }
+
+// Check() checks the consistency of a private key, returning an error
+// for each failed check: n = p*q, p and q are probable primes, e*d = 1
+// mod lambda(n), and the CRT fields agree with p, q and d.
+func Check(rsa *PrivateKey) []error {
+	var errs []error
+
+	if rsa.Version == nil || rsa.Modulus == nil ||
+	   rsa.PublicExponent == nil || rsa.PrivateExponent == nil ||
+	   rsa.Prime1 == nil || rsa.Prime2 == nil || rsa.Exponent1 == nil ||
+	   rsa.Exponent2 == nil || rsa.Coefficient == nil {
+		return append(errs, errors.New("missing key field"))
+	}
+	if rsa.Version.Sign() != 0 {
+		errs = append(errs, errors.New("unsupported key version"))
+	}
+
+	n, e, d := rsa.Modulus, rsa.PublicExponent, rsa.PrivateExponent
+	p, q := rsa.Prime1, rsa.Prime2
+	one := big.NewInt(1)
+	if p.Cmp(one) != 1 || q.Cmp(one) != 1 {
+		return append(errs, errors.New("p or q out of range"))
+	}
+	if new(big.Int).Mul(p, q).Cmp(n) != 0 {
+		errs = append(errs, errors.New("n != p*q"))
+	}
+	if p.ProbablyPrime(20) == false {
+		errs = append(errs, errors.New("p is not prime"))
+	}
+	if q.ProbablyPrime(20) == false {
+		errs = append(errs, errors.New("q is not prime"))
+	}
+
+	// lambda(n) = lcm(p-1, q-1)
+	pMinus := new(big.Int).Sub(p, one)
+	qMinus := new(big.Int).Sub(q, one)
+	gcd := new(big.Int).GCD(nil, nil, pMinus, qMinus)
+	lambda := new(big.Int).Mul(pMinus, qMinus)
+	lambda.Div(lambda, gcd)
+	ed := new(big.Int).Mul(e, d)
+	if ed.Mod(ed, lambda).Cmp(one) != 0 {
+		errs = append(errs, errors.New("e*d != 1 mod lambda(n)"))
+	}
+
+	if new(big.Int).Mod(d, pMinus).Cmp(rsa.Exponent1) != 0 {
+		errs = append(errs, errors.New("exponent1 != d mod (p-1)"))
+	}
+	if new(big.Int).Mod(d, qMinus).Cmp(rsa.Exponent2) != 0 {
+		errs = append(errs, errors.New("exponent2 != d mod (q-1)"))
+	}
+	c := new(big.Int).Mul(rsa.Coefficient, q)
+	if rsa.Coefficient.Cmp(p) != -1 || c.Mod(c, p).Cmp(one) != 0 {
+		errs = append(errs, errors.New("coefficient != q^-1 mod p"))
+	}
+
+	return errs
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// The pkcs1v15 module implements the generation and verification
+// of deterministic RSA signatures (RSASSA-PKCS1-v1_5) as specified
+// in PKCS#1v2.2. SHA-256 is used as the digest mechanism, except
+// for the SHA512 variants, which are needed for OpenSSH signatures.
+
+package pkcs1v15
+
+import (
+	"bytes"
+	"errors"
+	"godot/sha256"
+	"godot/sha512"
+	"io"
+	"math/big"
+)
+
+// The DER encoding of the DigestInfo of a SHA-256 digest, minus the
+// digest itself (section 9.2, note 1).
+var digestInfo = []byte {
+	0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86,
+	0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05,
+	0x00, 0x04, 0x20,
+}
+
+// Likewise, for a SHA-512 digest.
+var digestInfo512 = []byte {
+	0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86,
+	0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05,
+	0x00, 0x04, 0x40,
+}
+
+// digest() returns the DER encoding of the DigestInfo of the contents
+// of in, using SHA-512 if wide is true, and SHA-256 otherwise.
+func digest(in io.Reader, wide bool) ([]byte, error) {
+	var h, t []byte
+	var err error
+
+	if wide {
+		h, err = sha512.DigestAll(in)
+		t = digestInfo512
+	} else {
+		h, err = sha256.DigestAll(in)
+		t = digestInfo
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return append(append(make([]byte, 0), t...), h...), nil
+}
+
+// encode() implements the EMSA-PKCS1-v1_5 encoding operation (section
+// 9.2), returning an encoded blob of exactly emLen bytes.
+func encode(in io.Reader, emLen uint32, wide bool) ([]byte, error) {
+	// T is the DER encoding of the DigestInfo of the contents
+	// being signed.
+	t, err := digest(in, wide)
+	if err != nil {
+		return nil, err
+	}
+	if emLen < uint32(len(t)) + 11 {
+		return nil, errors.New("invalid msg len")
+	}
+
+	// EM = 0x00 || 0x01 || PS || 0x00 || T, where PS consists of
+	// 0xff bytes.
+	em := make([]byte, emLen)
+	em[1] = 0x01
+	for i := 2; i < len(em) - len(t) - 1; i++ {
+		em[i] = 0xff
+	}
+	copy(em[len(em) - len(t):], t)
+
+	return em, nil
+}
+
+// Encode() encodes the contents of in as an integer ready to be signed.
+func Encode(in io.Reader, emLen uint32) (*big.Int, error) {
+	em, err := encode(in, emLen, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(em), nil
+}
+
+// EncodeSHA512() is like Encode(), but uses SHA-512.
+func EncodeSHA512(in io.Reader, emLen uint32) (*big.Int, error) {
+	em, err := encode(in, emLen, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(em), nil
+}
+
+// verify() implements the verification operation (section 8.2.2,
+// steps 3 and 4). Rather than parsing em, the expected encoding is
+// recomputed and the two are compared byte by byte.
+func verify(in io.Reader, em []byte, emLen uint32, wide bool) (bool,
+    error) {
+	if uint32(len(em)) != emLen {
+		return false, errors.New("invalid signature")
+	}
+	t, err := encode(in, emLen, wide)
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(em, t), nil
+}
+
+// Verify() checks that em is the encoding of the contents of in.
+func Verify(in io.Reader, em []byte, emLen uint32) (bool, error) {
+	return verify(in, em, emLen, false)
+}
+
+// VerifySHA512() is like Verify(), but uses SHA-512.
+func VerifySHA512(in io.Reader, em []byte, emLen uint32) (bool, error) {
+	return verify(in, em, emLen, true)
+}
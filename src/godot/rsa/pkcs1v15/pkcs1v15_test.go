@@ -0,0 +1,46 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package pkcs1v15
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// EM for "abc" and an emLen of 64: 0x00 || 0x01 || PS || 0x00 || T,
+// where T is the SHA-256 DigestInfo prefix of RFC 8017, section 9.2,
+// note 1, followed by the digest of "abc" (FIPS 180-2, appendix B.1).
+const abcEM = "0001ffffffffffffffffffff00" +
+    "3031300d060960864801650304020105000420" +
+    "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"
+
+func TestEncode(t *testing.T) {
+	want, err := hex.DecodeString(abcEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	em, err := Encode(strings.NewReader("abc"), 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(em.FillBytes(make([]byte, 64)), want) == false {
+		t.Errorf("got %x", em)
+	}
+	ok, err := Verify(strings.NewReader("abc"), want, 64)
+	if err != nil || ok == false {
+		t.Errorf("verification failed: %v", err)
+	}
+	ok, err = Verify(strings.NewReader("abd"), want, 64)
+	if err != nil || ok {
+		t.Errorf("altered message verified")
+	}
+	// EM must fit T and at least 8 bytes of padding.
+	_, err = Encode(strings.NewReader("abc"), 61)
+	if err == nil {
+		t.Errorf("emLen 61 accepted")
+	}
+}
@@ -31,6 +31,15 @@ func byte2big(p []byte) *big.Int {
 	return new(big.Int).SetBytes(p)
 }
 
+// big2byte() transforms a *big.Int into a []byte of length l, which
+// must be large enough to hold it.
+func big2byte(x *big.Int, l uint32) []byte {
+	b := x.Bytes()
+	p := make([]byte, l)
+	copy(p[l - uint32(len(b)):], b)
+	return p
+}
+
 // intCeil() rounds the division of two integers up to the nearest
 // integer.
 func intCeil(a, b uint32) uint32 {
@@ -90,7 +99,7 @@ func Encode(in io.Reader, emBits uint32) (*big.Int, error) {
 		return nil, err
 	}
 	db := byte2big(append(append(make([]byte, 0), 0x01), salt...))
-	masked := new(big.Int).Xor(db, byte2big(mask)).Bytes()
+	masked := big2byte(new(big.Int).Xor(db, byte2big(mask)), mLen)
 	masked[0] &= byte(0xff >> (8 * emLen - emBits))
 
 	return byte2big(append(append(masked, h...), 0xbc)), nil
@@ -114,6 +123,9 @@ func Verify(in io.Reader, em []byte, emBits uint32) (bool, error) {
 	if emLen < sha256.Len + saltLen + 2 {
 		return false, errors.New("invalid msg len")
 	}
+	if uint32(len(em)) != emLen {
+		return false, errors.New("invalid signature")
+	}
 	masked, h, err := splitEncoded(em, len(em) - 1)
 	if err != nil {
 		return false, err
@@ -133,7 +145,7 @@ func Verify(in io.Reader, em []byte, emBits uint32) (bool, error) {
 	}
 	mask[0] &= byte(0xff >> (8 * emLen - emBits))
 	db := new(big.Int).Xor(byte2big(masked), byte2big(mask)).Bytes()
-	if db[0] != 0x01 || len(db) != sha256.Len + 1 {
+	if len(db) != sha256.Len + 1 || db[0] != 0x01 {
 		return false, errors.New("invalid signature")
 	}
 
@@ -7,94 +7,353 @@
 package rsa
 
 import (
-	"godot/rand"
+	"bytes"
+	"errors"
+	"fmt"
+	"godot/jwk"
 	"godot/rsa/pkcs1"
+	"godot/rsa/pkcs1v15"
 	"godot/rsa/pss"
+	"godot/rsa/ssh"
 	"godot/rsa/x509"
 	"godot/util"
 	"io"
 	"math/big"
+	"os"
 )
 
 type rsa struct {
-	pkcs1 *pkcs1.PrivateKey
-	x509  *pkcs1.PublicKey
+	pkcs1   *pkcs1.PrivateKey
+	x509    *pkcs1.PublicKey
+	padding string // "pss" (the default) or "pkcs1"
+	format  string // "pkcs1" (the default), "pkcs8" or "jwk"
+	pubFmt  string // "x509" (the default), "ssh" or "jwk"
+	comment string // comment of OpenSSH public keys
+	sigFmt  string // "raw" (the default) or "sshsig"
+	ns      string // namespace of sshsig signatures
 }
 
 func New() *rsa {
-	return new(rsa)
+	return &rsa{padding: "pss", format: "pkcs1", pubFmt: "x509",
+	    sigFmt: "raw", ns: "file"}
+}
+
+// Option() parses RSA-specific options.
+func (k *rsa) Option(op string, args []string, i *int) bool {
+	isSig := op == "sign" || op == "verify"
+
+	switch {
+	case isSig && (args[*i] == "-p" || args[*i] == "--padding"):
+		k.padding = util.GetArg(args, i)
+		if k.padding != "pss" && k.padding != "pkcs1" {
+			fmt.Fprintf(os.Stderr, "unsupported padding\n")
+			os.Exit(1)
+		}
+	case isSig && (args[*i] == "-f" || args[*i] == "--format"):
+		k.sigFmt = util.GetArg(args, i)
+		if k.sigFmt != "raw" && k.sigFmt != "sshsig" {
+			fmt.Fprintf(os.Stderr, "unsupported format\n")
+			os.Exit(1)
+		}
+	case isSig && (args[*i] == "-n" || args[*i] == "--namespace"):
+		k.ns = util.GetArg(args, i)
+		if k.ns == "" {
+			fmt.Fprintf(os.Stderr, "empty namespace\n")
+			os.Exit(1)
+		}
+	case op == "new" && (args[*i] == "-f" || args[*i] == "--format"):
+		err := k.SetFormat(util.GetArg(args, i))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			os.Exit(1)
+		}
+	case op == "pub" && (args[*i] == "-f" || args[*i] == "--format"):
+		k.pubFmt = util.GetArg(args, i)
+		if k.pubFmt != "x509" && k.pubFmt != "ssh" &&
+		   k.pubFmt != "jwk" {
+			fmt.Fprintf(os.Stderr, "unsupported format\n")
+			os.Exit(1)
+		}
+	case op == "pub" && args[*i] == "--comment":
+		k.comment = util.GetArg(args, i)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// SetFormat() selects the form of new private keys: "pkcs1", "pkcs8" or
+// "jwk".
+func (k *rsa) SetFormat(f string) error {
+	if f != "pkcs1" && f != "pkcs8" && f != "jwk" {
+		return errors.New("unsupported format")
+	}
+	k.format = f
+
+	return nil
+}
+
+// Describe() returns the names of the signature algorithm and hash, as
+// recorded in armored signatures. They depend on the padding and
+// signature format in use.
+func (k *rsa) Describe() (alg, hash string) {
+	switch {
+	case k.sigFmt == "sshsig":
+		return "SSHSIG", "SHA-512"
+	case k.padding == "pkcs1":
+		return "RSASSA-PKCS1-v1_5", "SHA-256"
+	default:
+		return "RSASSA-PSS", "SHA-256"
+	}
+}
+
+// JWSAlg() returns the default JWS algorithm for RSA keys.
+func (k *rsa) JWSAlg() string {
+	return "PS256"
+}
+
+// SetJWS() configures the padding for the JWS algorithm alg, as per
+// https://tools.ietf.org/rfc/rfc7518.txt, sections 3.3 and 3.5. The
+// PSS salt length is that of a SHA-256 digest, as required.
+func (k *rsa) SetJWS(alg string) error {
+	switch alg {
+	case "PS256":
+		k.padding = "pss"
+	case "RS256":
+		k.padding = "pkcs1"
+	default:
+		return errors.New("unsupported algorithm for an RSA key")
+	}
+	k.sigFmt = "raw"
+
+	return nil
 }
 
 // NewKey() creates a new l-bit long private key and writes it to w in
-// PEM format.
+// PEM format. If l is zero, a 4096-bit key is created. The key is
+// generated as specified in FIPS 186-5 (see keygen.go), and tested
+// before being written.
 func (k *rsa) NewKey(l int, w io.Writer) error {
-	p, err := rand.Prime(l/2)
+	switch l {
+	case 0:
+		l = 4096
+	case 2048, 3072, 4096, 8192:
+	default:
+		return errors.New("unsupported key size")
+	}
+	key, err := genKey(l, big.NewInt(65537))
 	if err != nil {
 		return err
 	}
-	q, err := rand.Prime(l/2)
+	k.pkcs1 = key
+	err = k.pairwiseTest()
 	if err != nil {
 		return err
 	}
-	n := new(big.Int).Mul(p, q)
-	e := big.NewInt(65537)
-	pMinus := new(big.Int).Sub(p, big.NewInt(1))
-	qMinus := new(big.Int).Sub(q, big.NewInt(1))
-	phi := new(big.Int).Mul(pMinus, qMinus)
-	d := new(big.Int).ModInverse(e, phi)
-
-	k.pkcs1 = new(pkcs1.PrivateKey)
-	k.pkcs1.Version = big.NewInt(0)
-	k.pkcs1.Prime1 = p
-	k.pkcs1.Prime2 = q
-	k.pkcs1.Modulus = n
-	k.pkcs1.PublicExponent = e
-	k.pkcs1.PrivateExponent = d
-	k.pkcs1.Exponent1 = new(big.Int).Mod(d, pMinus)
-	k.pkcs1.Exponent2 = new(big.Int).Mod(d, qMinus)
-	k.pkcs1.Coefficient = new(big.Int).ModInverse(q, p)
 
-	return pkcs1.Write(k.pkcs1, w)
+	switch k.format {
+	case "pkcs8":
+		return pkcs1.WritePKCS8(k.pkcs1, w)
+	case "jwk":
+		j, err := k.toJWK(false)
+		if err != nil {
+			return err
+		}
+		return j.Write(w)
+	default:
+		return pkcs1.Write(k.pkcs1, w)
+	}
 }
 
-// LoadPriv() loads a private key from r.
+// LoadPriv() loads a private key from r, in PKCS1, PKCS#8 or JWK form.
 func (k *rsa) LoadPriv(r io.Reader) error {
 	var err error
-	k.pkcs1, err = pkcs1.Read(r)
+	body := util.ReadAll(r)
+	if jwk.IsKey(body) {
+		j, err := jwk.Read(bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		k.pkcs1, err = privFromJWK(j)
+		return err
+	}
+	k.pkcs1, err = pkcs1.Read(bytes.NewReader(body))
 	return err
 }
 
-// LoadPub() loads a public key from r.
+// LoadPub() loads a public key from r, either in X.509 or JWK form or
+// as an OpenSSH public key line.
 func (k *rsa) LoadPub(r io.Reader) error {
 	var err error
-	k.x509, err = x509.Read(r)
+	body := util.ReadAll(r)
+	if jwk.IsKey(body) {
+		j, err := jwk.Read(bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		k.x509, err = pubFromJWK(j)
+		return err
+	}
+	if util.IsPEM(body) == false && ssh.IsKey(body) {
+		k.x509, err = ssh.Read(bytes.NewReader(body))
+	} else {
+		k.x509, err = x509.Read(bytes.NewReader(body))
+	}
 	return err
 }
 
-// WritePub() writes a public key to w.
+// publicKey() returns the public half of the private key.
+func (k *rsa) publicKey() *pkcs1.PublicKey {
+	pub := new(pkcs1.PublicKey)
+	pub.Modulus = k.pkcs1.Modulus
+	pub.PublicExponent = k.pkcs1.PublicExponent
+
+	return pub
+}
+
+// WritePub() writes a public key to w, in X.509 form unless an OpenSSH
+// public key or a JWK was requested.
 func (k *rsa) WritePub(w io.Writer) error {
-	return x509.Write(k.pkcs1, w)
+	switch k.pubFmt {
+	case "ssh":
+		return ssh.Write(k.publicKey(), k.comment, w)
+	case "jwk":
+		j, err := k.toJWK(true)
+		if err != nil {
+			return err
+		}
+		return j.Write(w)
+	default:
+		return x509.Write(k.pkcs1, w)
+	}
+}
+
+// Check() checks the consistency of a private key.
+func (k *rsa) Check() []error {
+	return pkcs1.Check(k.pkcs1)
+}
+
+// emBits() returns the maximal bit length of an encoded message for a
+// modulus n, as defined in section 8.1.1 of PKCS#1v2.2.
+func emBits(n *big.Int) uint32 {
+	return uint32(n.BitLen() - 1)
+}
+
+// i2osp() converts x into a big-endian byte string of length l, as per
+// section 4.1 of PKCS#1v2.2.
+func i2osp(x *big.Int, l int) ([]byte, error) {
+	b := x.Bytes()
+	if len(b) > l {
+		return nil, errors.New("integer too large")
+	}
+	p := make([]byte, l)
+	copy(p[l - len(b):], b)
+
+	return p, nil
+}
+
+// modLen() returns the length in bytes of a modulus n.
+func modLen(n *big.Int) int {
+	return (n.BitLen() + 7) / 8
+}
+
+// rsasp1() implements the RSA signature primitive (section 5.2.1 of
+// PKCS#1v2.2) using the Chinese Remainder Theorem, i.e. the second
+// representation of the private key. Before being returned, the
+// signature is checked against the public exponent, so that a fault
+// in the computation cannot leak the private key.
+func (k *rsa) rsasp1(m *big.Int) (*big.Int, error) {
+	key := k.pkcs1
+	p, q := key.Prime1, key.Prime2
+
+	// s1 = m^dP mod p, s2 = m^dQ mod q
+	s1 := new(big.Int).Exp(m, key.Exponent1, p)
+	s2 := new(big.Int).Exp(m, key.Exponent2, q)
+
+	// h = (s1 - s2) * qInv mod p, s = s2 + q * h
+	h := new(big.Int).Sub(s1, s2)
+	h.Mul(h, key.Coefficient)
+	h.Mod(h, p)
+	s := h.Mul(h, q)
+	s.Add(s, s2)
+
+	if new(big.Int).Exp(s, key.PublicExponent, key.Modulus).Cmp(m) != 0 {
+		return nil, errors.New("fault detected in signature")
+	}
+
+	return s, nil
+}
+
+// rsavp1() implements the RSA verification primitive (section 5.2.2
+// of PKCS#1v2.2) on a signature, which must be as long as the modulus.
+func (k *rsa) rsavp1(body []byte) (*big.Int, error) {
+	e := k.x509.PublicExponent
+	n := k.x509.Modulus
+	if len(body) != modLen(n) {
+		return nil, errors.New("invalid signature length")
+	}
+	s := new(big.Int).SetBytes(body)
+	if s.Cmp(n) != -1 {
+		return nil, errors.New("invalid signature")
+	}
+
+	return new(big.Int).Exp(s, e, n), nil
 }
 
 // Sign() generates a signature of m and writes it to w.
 func (k *rsa) Sign(m io.Reader, w io.Writer) error {
-	h, err := pss.Encode(m, 4095)
+	var h *big.Int
+	var err error
+
+	if k.sigFmt == "sshsig" {
+		return k.signSSH(m, w)
+	}
+	n := k.pkcs1.Modulus
+	switch k.padding {
+	case "pkcs1":
+		h, err = pkcs1v15.Encode(m, uint32(modLen(n)))
+	default:
+		h, err = pss.Encode(m, emBits(n))
+	}
 	if err != nil {
 		return err
 	}
-	d := k.pkcs1.PrivateExponent
-	n := k.pkcs1.Modulus
-	s := new(big.Int).Exp(h, d, n)
-	w.Write(s.Bytes())
+	x, err := k.rsasp1(h)
+	if err != nil {
+		return err
+	}
+	s, err := i2osp(x, modLen(n))
+	if err != nil {
+		return err
+	}
+	w.Write(s)
 	return nil
 }
 
 // Verify() checks if t is a valid signature of m.
 func (k *rsa) Verify(t, m io.Reader) (bool, error) {
-	body := util.ReadAll(t)
-	e := k.x509.PublicExponent
+	if k.sigFmt == "sshsig" {
+		return k.verifySSH(t, m)
+	}
 	n := k.x509.Modulus
-	s := new(big.Int).SetBytes(body)
-	h := new(big.Int).Exp(s, e, n)
-	return pss.Verify(m, h.Bytes(), 4095)
+	h, err := k.rsavp1(util.ReadAll(t))
+	if err != nil {
+		return false, err
+	}
+
+	switch k.padding {
+	case "pkcs1":
+		em, err := i2osp(h, modLen(n))
+		if err != nil {
+			return false, errors.New("invalid signature")
+		}
+		return pkcs1v15.Verify(m, em, uint32(modLen(n)))
+	default:
+		em, err := i2osp(h, (int(emBits(n)) + 7) / 8)
+		if err != nil {
+			return false, errors.New("invalid signature")
+		}
+		return pss.Verify(m, em, emBits(n))
+	}
 }
@@ -0,0 +1,185 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// The ssh module exports functions that allow RSA public keys to be
+// written and read in the OpenSSH format, i.e. as a line of an
+// authorized_keys file, and signatures in the format of ssh-keygen -Y
+// (see sshsig.go).
+
+package ssh
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"godot/rsa/pkcs1"
+	"io"
+	"math/big"
+)
+
+var (
+	ErrBadKey = errors.New("ssh: invalid key")
+	ErrNoKey  = errors.New("ssh: no ssh-rsa key found")
+)
+
+// The key type of an RSA public key, as per
+// https://tools.ietf.org/rfc/rfc4253.txt, section 6.6.
+const KeyType = "ssh-rsa"
+
+// putString() appends an SSH string to b, as per
+// https://tools.ietf.org/rfc/rfc4251.txt, section 5.
+func putString(b *bytes.Buffer, s []byte) {
+	var l [4]byte
+
+	binary.BigEndian.PutUint32(l[:], uint32(len(s)))
+	b.Write(l[:])
+	b.Write(s)
+}
+
+// putMpint() appends a non-negative SSH mpint to b. A zero byte is
+// prepended if the most significant bit of x is set, so that x is not
+// taken to be negative.
+func putMpint(b *bytes.Buffer, x *big.Int) {
+	p := x.Bytes()
+	if len(p) > 0 && p[0] & 0x80 != 0 {
+		p = append([]byte{0}, p...)
+	}
+	putString(b, p)
+}
+
+// getString() consumes an SSH string from the head of p.
+func getString(p *[]byte) ([]byte, error) {
+	if len(*p) < 4 {
+		return nil, ErrBadKey
+	}
+	l := binary.BigEndian.Uint32(*p)
+	if uint64(l) > uint64(len(*p) - 4) {
+		return nil, ErrBadKey
+	}
+	s := (*p)[4:4 + l]
+	*p = (*p)[4 + l:]
+
+	return s, nil
+}
+
+// getMpint() consumes a positive SSH mpint from the head of p. The
+// encoding must be minimal.
+func getMpint(p *[]byte) (*big.Int, error) {
+	s, err := getString(p)
+	if err != nil {
+		return nil, err
+	}
+	if len(s) == 0 || s[0] & 0x80 != 0 ||
+	   (s[0] == 0 && (len(s) == 1 || s[1] & 0x80 == 0)) {
+		return nil, ErrBadKey
+	}
+
+	return new(big.Int).SetBytes(s), nil
+}
+
+// Marshal() encodes an RSA public key in the SSH wire format.
+func Marshal(pub *pkcs1.PublicKey) []byte {
+	var b bytes.Buffer
+
+	putString(&b, []byte(KeyType))
+	putMpint(&b, pub.PublicExponent)
+	putMpint(&b, pub.Modulus)
+
+	return b.Bytes()
+}
+
+// Unmarshal() decodes an RSA public key in the SSH wire format.
+func Unmarshal(blob []byte) (*pkcs1.PublicKey, error) {
+	var pub = new(pkcs1.PublicKey)
+
+	t, err := getString(&blob)
+	if err != nil {
+		return nil, err
+	}
+	if string(t) != KeyType {
+		return nil, ErrBadKey
+	}
+	pub.PublicExponent, err = getMpint(&blob)
+	if err != nil {
+		return nil, err
+	}
+	pub.Modulus, err = getMpint(&blob)
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) != 0 {
+		return nil, ErrBadKey
+	}
+
+	return pub, nil
+}
+
+// Write() writes an RSA public key to w as an OpenSSH public key line,
+// followed by comment if not empty.
+func Write(pub *pkcs1.PublicKey, comment string, w io.Writer) error {
+	line := KeyType + " " + base64.StdEncoding.EncodeToString(Marshal(pub))
+	if comment != "" {
+		line += " " + comment
+	}
+	_, err := io.WriteString(w, line + "\n")
+
+	return err
+}
+
+// findKey() returns the base64-encoded key in an authorized_keys
+// line, which may be preceded by options. ok is false if the line does
+// not hold an ssh-rsa key.
+func findKey(line []byte) (key []byte, ok bool) {
+	f := bytes.Fields(line)
+	for i := 0; i + 1 < len(f); i++ {
+		if string(f[i]) == KeyType {
+			return f[i + 1], true
+		}
+	}
+
+	return nil, false
+}
+
+// IsKey() tells whether body holds an OpenSSH RSA public key.
+func IsKey(body []byte) bool {
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		if _, ok := findKey(line); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Read() reads the first RSA public key in an authorized_keys-style
+// file. Empty lines, comments, and keys of other types are skipped.
+func Read(r io.Reader) (*pkcs1.PublicKey, error) {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := bytes.TrimSpace(s.Bytes())
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		key, ok := findKey(line)
+		if ok == false {
+			continue
+		}
+		blob, err := base64.StdEncoding.DecodeString(string(key))
+		if err != nil {
+			return nil, err
+		}
+		return Unmarshal(blob)
+	}
+	if s.Err() != nil {
+		return nil, s.Err()
+	}
+
+	return nil, ErrNoKey
+}
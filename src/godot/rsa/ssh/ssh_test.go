@@ -0,0 +1,81 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package ssh
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+// The public half of a 1024-bit key, as printed by ssh-keygen -y, with
+// a comment appended, and the key's modulus, as printed by openssl rsa
+// -modulus.
+const sshKey = "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAAAgQDfNaE3tJeCTV3SxfjhzS1K" +
+    "R6mYUJjmRTwWn8gnHi0SfHTB4l0ZgSa9tnxP9/1//+ccFGV84UWhjH+x9lry" +
+    "jY+qAmAS2/MiSKn0WfhnfyQszwbT+CEj85p5PyLUeUQoCIqRjosuUAO1ZIF4" +
+    "qwbJT+Rkaks1N/4S06egRi5p30o83w== godot@example"
+
+const sshModulus = "df35a137b497824d5dd2c5f8e1cd2d4a47a9985098e6453c169f" +
+    "c8271e2d127c74c1e25d198126bdb67c4ff7fd7fffe71c14657c" +
+    "e145a18c7fb1f65af28d8faa026012dbf32248a9f459f8677f24" +
+    "2ccf06d3f82123f39a793f22d4794428088a918e8b2e5003b564" +
+    "8178ab06c94fe4646a4b3537fe12d3a7a0462e69df4a3cdf"
+
+func TestRead(t *testing.T) {
+	n, err := hex.DecodeString(sshModulus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := "# a comment\n\nssh-ed25519 AAAA x\n" +
+	    "no-pty " + sshKey + "\n"
+	if IsKey([]byte(body)) == false {
+		t.Fatal("IsKey: key not found")
+	}
+	pub, err := Read(strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub.Modulus.Cmp(new(big.Int).SetBytes(n)) != 0 {
+		t.Errorf("modulus mismatch")
+	}
+	if pub.PublicExponent.Int64() != 65537 {
+		t.Errorf("exponent mismatch")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	var b bytes.Buffer
+
+	pub, err := Read(strings.NewReader(sshKey))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Write(pub, "godot@example", &b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != sshKey + "\n" {
+		t.Errorf("got %q, want %q", b.String(), sshKey + "\n")
+	}
+}
+
+func TestNoKey(t *testing.T) {
+	body := "# a comment\nssh-ed25519 AAAA x\n"
+	if IsKey([]byte(body)) {
+		t.Errorf("IsKey: unexpected key")
+	}
+	_, err := Read(strings.NewReader(body))
+	if err != ErrNoKey {
+		t.Errorf("got %v, want %v", err, ErrNoKey)
+	}
+	_, err = Unmarshal([]byte{0, 0, 0, 7, 's', 's', 'h', '-', 'd', 's',
+	    's'})
+	if err != ErrBadKey {
+		t.Errorf("got %v, want %v", err, ErrBadKey)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// sshsig.go implements the signature format of ssh-keygen -Y, as
+// described in OpenSSH's PROTOCOL.sshsig.
+
+package ssh
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"godot/sha256"
+	"godot/sha512"
+	"io"
+	"io/ioutil"
+)
+
+var (
+	ErrBadSig = errors.New("ssh: invalid signature")
+)
+
+// The PEM type of an armored signature.
+const PemType = "SSH SIGNATURE"
+
+// The algorithms of RSA signatures, as per
+// https://tools.ietf.org/rfc/rfc8332.txt, section 3.
+const (
+	SigSHA256 = "rsa-sha2-256"
+	SigSHA512 = "rsa-sha2-512"
+)
+
+const (
+	magic   = "SSHSIG"
+	version = 1
+)
+
+// A Signature holds the fields of an sshsig blob.
+type Signature struct {
+	PublicKey	[]byte // the signer's key, in the SSH wire format
+	Namespace	string
+	HashAlg		string // "sha256" or "sha512"
+	SigAlg		string // SigSHA256 or SigSHA512
+	Sig		[]byte // the RSA signature of SignedData()
+}
+
+// Digest() hashes the contents of m with the message hash algorithm
+// alg, which must be "sha256" or "sha512".
+func Digest(alg string, m io.Reader) ([]byte, error) {
+	switch alg {
+	case "sha256":
+		return sha256.DigestAll(m)
+	case "sha512":
+		return sha512.DigestAll(m)
+	default:
+		return nil, ErrBadSig
+	}
+}
+
+// SignedData() returns the blob that is signed by the RSA key: the
+// namespace ns, the hash algorithm alg, and the message digest h.
+func SignedData(ns, alg string, h []byte) []byte {
+	var b bytes.Buffer
+
+	b.WriteString(magic)
+	putString(&b, []byte(ns))
+	putString(&b, nil) // reserved
+	putString(&b, []byte(alg))
+	putString(&b, h)
+
+	return b.Bytes()
+}
+
+// Marshal() encodes a signature as an sshsig blob.
+func (sig *Signature) Marshal() []byte {
+	var b, s bytes.Buffer
+	var v [4]byte
+
+	putString(&s, []byte(sig.SigAlg))
+	putString(&s, sig.Sig)
+
+	b.WriteString(magic)
+	binary.BigEndian.PutUint32(v[:], version)
+	b.Write(v[:])
+	putString(&b, sig.PublicKey)
+	putString(&b, []byte(sig.Namespace))
+	putString(&b, nil) // reserved
+	putString(&b, []byte(sig.HashAlg))
+	putString(&b, s.Bytes())
+
+	return b.Bytes()
+}
+
+// Unmarshal() decodes an sshsig blob.
+func (sig *Signature) Unmarshal(p []byte) (*Signature, error) {
+	if len(p) < len(magic) + 4 || string(p[:len(magic)]) != magic ||
+	   binary.BigEndian.Uint32(p[len(magic):]) != version {
+		return nil, ErrBadSig
+	}
+	p = p[len(magic) + 4:]
+
+	var f [5][]byte
+	for i := range f {
+		s, err := getString(&p)
+		if err != nil {
+			return nil, ErrBadSig
+		}
+		f[i] = s
+	}
+	if len(p) != 0 {
+		return nil, ErrBadSig
+	}
+	s := f[4]
+	alg, err := getString(&s)
+	if err != nil {
+		return nil, ErrBadSig
+	}
+	raw, err := getString(&s)
+	if err != nil || len(s) != 0 {
+		return nil, ErrBadSig
+	}
+	sig.PublicKey = f[0]
+	sig.Namespace = string(f[1])
+	sig.HashAlg = string(f[3])
+	sig.SigAlg = string(alg)
+	sig.Sig = raw
+
+	return sig, nil
+}
+
+// Write() writes an armored signature to w.
+func (sig *Signature) Write(w io.Writer) error {
+	return pem.Encode(w, &pem.Block{Type: PemType, Bytes: sig.Marshal()})
+}
+
+// ReadSignature() reads an armored signature from r.
+func ReadSignature(r io.Reader) (*Signature, error) {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	blob, _ := pem.Decode(body)
+	if blob == nil || blob.Type != PemType {
+		return nil, ErrBadSig
+	}
+
+	return new(Signature).Unmarshal(blob.Bytes)
+}
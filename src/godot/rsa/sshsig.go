@@ -0,0 +1,88 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// This file implements RSA signatures in the format of ssh-keygen -Y,
+// using RSASSA-PKCS1-v1_5 as mandated by RFC 8332.
+
+package rsa
+
+import (
+	"bytes"
+	"errors"
+	"godot/rsa/pkcs1v15"
+	"godot/rsa/ssh"
+	"io"
+)
+
+// signSSH() generates an armored sshsig signature of m and writes it
+// to w. As done by ssh-keygen, SHA-512 is used throughout.
+func (k *rsa) signSSH(m io.Reader, w io.Writer) error {
+	var sig = new(ssh.Signature)
+
+	h, err := ssh.Digest("sha512", m)
+	if err != nil {
+		return err
+	}
+	data := ssh.SignedData(k.ns, "sha512", h)
+	n := k.pkcs1.Modulus
+	em, err := pkcs1v15.EncodeSHA512(bytes.NewReader(data),
+	    uint32(modLen(n)))
+	if err != nil {
+		return err
+	}
+	x, err := k.rsasp1(em)
+	if err != nil {
+		return err
+	}
+	s, err := i2osp(x, modLen(n))
+	if err != nil {
+		return err
+	}
+
+	sig.PublicKey = ssh.Marshal(k.publicKey())
+	sig.Namespace = k.ns
+	sig.HashAlg = "sha512"
+	sig.SigAlg = ssh.SigSHA512
+	sig.Sig = s
+
+	return sig.Write(w)
+}
+
+// verifySSH() checks if t is a valid sshsig signature of m, made by
+// the loaded key in the chosen namespace.
+func (k *rsa) verifySSH(t, m io.Reader) (bool, error) {
+	sig, err := ssh.ReadSignature(t)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(sig.PublicKey, ssh.Marshal(k.x509)) == false {
+		return false, errors.New("signature made by another key")
+	}
+	if sig.Namespace != k.ns {
+		return false, errors.New("signature namespace mismatch")
+	}
+	h, err := ssh.Digest(sig.HashAlg, m)
+	if err != nil {
+		return false, err
+	}
+	data := bytes.NewReader(ssh.SignedData(sig.Namespace, sig.HashAlg, h))
+
+	n := k.x509.Modulus
+	x, err := k.rsavp1(sig.Sig)
+	if err != nil {
+		return false, err
+	}
+	em, err := i2osp(x, modLen(n))
+	if err != nil {
+		return false, errors.New("invalid signature")
+	}
+	switch sig.SigAlg {
+	case ssh.SigSHA256:
+		return pkcs1v15.Verify(data, em, uint32(modLen(n)))
+	case ssh.SigSHA512:
+		return pkcs1v15.VerifySHA512(data, em, uint32(modLen(n)))
+	default:
+		return false, errors.New("unsupported signature algorithm")
+	}
+}
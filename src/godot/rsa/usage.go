@@ -15,37 +15,98 @@ func (k *rsa) UsageError() {
 
 The supported commands are:
 
-godot rsa new [-o <file>]
+godot rsa new [-b <bits>] [-f <format>] [--encrypt] [--pass <src>]
+    [--outform <form>] [-o <file>]
 
-	Creates a new 4096-bit RSA private key. If -o is specified,
-	the key is written to <file> instead of stdout.
+	Creates a new RSA private key. If -b is specified, the modulus
+	is <bits> long, which must be one of 2048, 3072, 4096 or 8192;
+	otherwise a 4096-bit modulus is used. If -f is specified,
+	<format> selects the form of the key: pkcs1 for a PKCS1 "RSA
+	PRIVATE KEY", pkcs8 for a PKCS#8 "PRIVATE KEY", or jwk for a
+	JSON Web Key; otherwise pkcs1 is used. All forms are accepted
+	wherever godot reads a private key. If --encrypt is specified,
+	the key is written as a PKCS#8 "ENCRYPTED PRIVATE KEY", using
+	PBES2 with PBKDF2-HMAC-SHA256 and AES-256-CBC, and -f, if
+	specified, must be pkcs8. The passphrase is read from <src> as
+	described below, or prompted for twice on the terminal if
+	--pass is not specified. If -o is specified, the key is written
+	to <file> instead of stdout.
 
-godot rsa pub [-i <file>] [-o <file>]
+godot rsa pub [-i <file>] [--inform <form>] [--pass <src>] [-f <format>]
+    [--comment <text>] [--outform <form>] [-o <file>]
 
-	Derives a public key from a private key. If -i is specified,
-	the key is read from <file> instead of stdin. The key must be
-	a 4096-bit RSA private key. If -o is specified, the public
-	key is written to <file> instead of stdout.
+	Derives a public key from a private key. If -i is specified, the
+	key is read from <file> instead of stdin. The key must be an RSA
+	private key. If -o is specified, the public key is written to
+	<file> instead of stdout. If -f is specified, <format> selects
+	the form of the public key: x509 for a X.509 "PUBLIC KEY", ssh
+	for an OpenSSH "ssh-rsa" line, followed by <text> if --comment
+	is specified, or jwk for a JSON Web Key; otherwise x509 is used.
+	If the key is encrypted, the passphrase is read from <src>,
+	which is either env:<var> for the environment variable <var>, or
+	fd:<n> for the first line read from file descriptor <n>;
+	otherwise it is prompted for on the terminal.
 
-godot rsa sign -k <file> [-i <file>] [-o <file>]
+godot rsa sign -k <file> [--inform <form>] [--pass <src>] [-p <padding>]
+    [-f <format>] [-n <namespace>] [--sig-format <form>] [-i <file>]
+    [-o <file>]
 
-	Generates a 4096-bit RSA signature following the Probabilistic
-	Signature Scheme (PSS) with SHA-256 as the digest mechanism.
-	The -k parameter must be specified, and <file> must point to a
-	4096-bit RSA private key. If -i is specified, the contents to
-	be signed are read from <file> instead of stdin. If -o is
-	specified, the resulting signature is written to <file> instead
-	of stdout. The signature is always written in binary format.
+	Generates an RSA signature with SHA-256 as the digest
+	mechanism. If -p is specified, <padding> selects the signature
+	scheme: pss for the Probabilistic Signature Scheme (PSS), or
+	pkcs1 for RSASSA-PKCS1-v1_5; otherwise PSS is used. The -k
+	parameter must be specified, and <file> must point to an RSA
+	private key, whose passphrase is read from <src> as described
+	for pub. The size of the signature matches that of the key's
+	modulus. If -i is specified, the contents to be signed are
+	read from <file> instead of stdin. If -o is specified, the
+	resulting signature is written to <file> instead of stdout. If
+	-f is specified, <format> selects the form of the signature:
+	raw for the signature alone, or sshsig for an armored "SSH
+	SIGNATURE" as created by ssh-keygen -Y sign; otherwise raw is
+	used. sshsig signatures use rsa-sha2-512, so -p is ignored, are
+	bound to <namespace> if -n is specified, or to the "file"
+	namespace otherwise, and are always armored, so --sig-format
+	cannot be used.
 
-godot rsa verify -k <file> -s <file> [-i <file>]
+godot rsa verify -k <file> [--inform <form>] -s <file> [-p <padding>]
+    [-f <format>] [-n <namespace>] [-i <file>]
 
-	Verifies a 4096-bit RSA PSS signature with SHA-256 as the
-	digest mechanism. The -k and -s parameters must be specified
-	and must point to a 4096-bit RSA public key and PSS signature
-	respectively. If -i is specified, the data whose signature is
-	being verified is read from <file> instead of stdin.
+	Verifies an RSA signature with SHA-256 as the digest mechanism.
+	The -k and -s parameters must be specified and must point to an
+	RSA public key and signature respectively. The public key may
+	also be a JSON Web Key, or an OpenSSH "ssh-rsa" key, in which
+	case the first such key in an authorized_keys-style <file> is
+	used. If -p is specified, <padding> selects the signature scheme
+	as described for sign; otherwise PSS is assumed. If -f is
+	specified, <format> is as described for sign; sshsig signatures
+	must have been made by the given key, with rsa-sha2-256 or
+	rsa-sha2-512, in <namespace> or the "file" namespace. If -i is
+	specified, the data whose signature is being verified is read
+	from <file> instead of stdin.
 
---{binary,in,key,out} can be used instead of -{b,i,k,o}.
+godot rsa check [-i <file>] [--pass <src>]
+
+	Checks the consistency of a private key: that n = p*q, that p
+	and q are probable primes, that e*d = 1 mod lambda(n), and that
+	the CRT parameters agree with p, q and d. If -i is specified,
+	the key is read from <file> instead of stdin. --pass is as
+	described for pub. Each failed check is reported, and godot
+	exits with a non-zero status if any check fails.
+
+--inform and --outform select the form in which keys are read and
+written: der or pem. Keys are written in PEM format unless --outform
+der is specified, and read in either format unless --inform is
+specified.
+
+--sig-format selects the form in which signatures are written: binary,
+hex, base64, or pem for a "GODOT SIGNATURE" that also records the
+algorithm and hash used. Signatures are written in binary format
+unless --sig-format is specified, and read in any of these forms; the
+algorithm and hash recorded in a "GODOT SIGNATURE" must match.
+
+--{bits,format,in,key,namespace,out,padding,sig} can be used instead
+of -{b,f,i,k,n,o,p,s}.
 `)
 	os.Exit(1)
 }
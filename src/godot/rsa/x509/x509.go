@@ -12,6 +12,7 @@ import (
 	"encoding/pem"
 	"errors"
 	"godot/rsa/pkcs1"
+	"godot/util"
 	"io"
 	"io/ioutil"
 )
@@ -28,16 +29,13 @@ type PUBKEY struct {
 	Body		asn1.BitString
 }
 
-// As per https://tools.ietf.org/rfc/rfc3279.txt, 2.3.1
-var rsaEncryption asn1.ObjectIdentifier = []int{1, 2, 840, 113549, 1, 1, 1}
-
 // wrap() transforms a PKCS1 private key in a X.509 public key.
 func wrap(rsa *pkcs1.PrivateKey) (*PUBKEY, error) {
 	var x509 = new(PUBKEY)
 	var rsaPub = new(pkcs1.PublicKey)
 	var err error
 
-	x509.Type.OID = rsaEncryption;
+	x509.Type.OID = pkcs1.OID;
 	x509.Type.NULL.Tag = 5 // NULL tag
 	rsaPub.Modulus = rsa.Modulus
 	rsaPub.PublicExponent = rsa.PublicExponent
@@ -53,7 +51,7 @@ func wrap(rsa *pkcs1.PrivateKey) (*PUBKEY, error) {
 func unwrap(x509 *PUBKEY) (*pkcs1.PublicKey, error) {
 	var rsaPub = new(pkcs1.PublicKey)
 
-	if rsaEncryption.Equal(x509.Type.OID) == false ||
+	if pkcs1.OID.Equal(x509.Type.OID) == false ||
 	   x509.Type.NULL.Tag != 5 ||
 	   x509.Type.NULL.IsCompound != false ||
 	   len(x509.Type.NULL.Bytes) != 0 {
@@ -87,8 +85,8 @@ func Write(rsa *pkcs1.PrivateKey, w io.Writer) error {
 	return pem.Encode(w, blob)
 }
 
-// Read() reads a X.509 public key from r, transforms it in a PKCS1
-// public key, and returns it.
+// Read() reads a X.509 public key in PEM or DER format from r,
+// transforms it in a PKCS1 public key, and returns it.
 func Read(r io.Reader) (*pkcs1.PublicKey, error) {
 	var x509 = new(PUBKEY)
 
@@ -96,14 +94,14 @@ func Read(r io.Reader) (*pkcs1.PublicKey, error) {
 	if err != nil {
 		return nil, err
 	}
-	blob, _ := pem.Decode(body)
-	if blob == nil {
+	der, t, ok := util.Unarmor(body)
+	if ok == false {
 		return nil, errors.New("pem decode error")
 	}
-	if blob.Type != "PUBLIC KEY" || blob.Bytes == nil {
+	if (t != "" && t != "PUBLIC KEY") || der == nil {
 		return nil, errors.New("invalid pem")
 	}
-	_, err = asn1.Unmarshal(blob.Bytes, x509)
+	_, err = asn1.Unmarshal(der, x509)
 	if err != nil {
 		return nil, err
 	}
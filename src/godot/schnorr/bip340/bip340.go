@@ -0,0 +1,211 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// The bip340 module implements Schnorr signatures over secp256k1 as
+// specified in BIP-340. Public keys are x-only: a point is identified
+// by its x-coordinate alone, its y-coordinate being taken to be even.
+
+package bip340
+
+import (
+	"errors"
+	"godot/ecdsa/prime"
+	"godot/ecdsa/secp256k1"
+	"godot/sha256"
+	"math/big"
+)
+
+const (
+	KeyLen = 32 // bytes in an x-only public key
+	SigLen = 64 // bytes in a signature
+)
+
+var (
+	ErrBadKey = errors.New("bip340: invalid key")
+	ErrBadSig = errors.New("bip340: invalid signature")
+)
+
+// taggedHash() computes SHA-256(SHA-256(tag) || SHA-256(tag) || x).
+func taggedHash(tag string, x ...[]byte) ([]byte, error) {
+	t, err := sha256.DigestBytes([]byte(tag))
+	if err != nil {
+		return nil, err
+	}
+	m := append(append(make([]byte, 0), t...), t...)
+	for _, p := range x {
+		m = append(m, p...)
+	}
+
+	return sha256.DigestBytes(m)
+}
+
+// bytes32() encodes v as a 32-byte big-endian integer.
+func bytes32(v *big.Int) []byte {
+	b := v.Bytes()
+	p := make([]byte, 32)
+	copy(p[32 - len(b):], b)
+	return p
+}
+
+// hasEvenY() checks whether the y-coordinate of q is even.
+func hasEvenY(q *prime.Point) bool {
+	return q.GetY().Bit(0) == 0
+}
+
+// liftX() returns the point whose x-coordinate is x and whose
+// y-coordinate is even.
+func liftX(x *big.Int) (*prime.Point, error) {
+	cp := secp256k1.Params
+	p := cp.P
+	if x.Cmp(p) != -1 {
+		return nil, ErrBadKey
+	}
+
+	// c = x^3 + 7, y = c^((p+1)/4)
+	f, c, _, err := cp.Get()
+	if err != nil {
+		return nil, err
+	}
+	xF := f.Element(x)
+	cF := f.NewElement().Exp(xF, f.Int64(3))
+	cF.Add(cF, f.Int64(7))
+	e := new(big.Int).Add(p, big.NewInt(1))
+	y := f.NewElement().Exp(cF, f.Element(e.Rsh(e, 2)))
+	if f.NewElement().Mul(y, y).Cmp(cF) != 0 {
+		return nil, ErrBadKey
+	}
+	if y.GetValue().Bit(0) != 0 {
+		y = f.NewElement().Neg(y)
+	}
+
+	return c.NewPoint().Set(xF, y), nil
+}
+
+// Public() returns the x-only public key of a private key d.
+func Public(d *big.Int) ([]byte, error) {
+	cp := secp256k1.Params
+	if d.Sign() != 1 || d.Cmp(cp.N) != -1 {
+		return nil, ErrBadKey
+	}
+	q, err := cp.Public(d)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes32(q.GetX()), nil
+}
+
+// Sign() signs a message m with a private key d, using the auxiliary
+// random data aux.
+func Sign(d *big.Int, m, aux []byte) ([]byte, error) {
+	cp := secp256k1.Params
+	n := cp.N
+	if d.Sign() != 1 || d.Cmp(n) != -1 {
+		return nil, ErrBadKey
+	}
+	if len(aux) != 32 {
+		return nil, errors.New("invalid auxiliary data")
+	}
+	_, c, g, err := cp.Get()
+	if err != nil {
+		return nil, err
+	}
+	P := c.NewPoint().MulSecret(g, d, n)
+	if hasEvenY(P) == false {
+		d = new(big.Int).Sub(n, d)
+	}
+	pk := bytes32(P.GetX())
+
+	// t = bytes(d) xor hash_aux(a)
+	t, err := taggedHash("BIP0340/aux", aux)
+	if err != nil {
+		return nil, err
+	}
+	for i, b := range bytes32(d) {
+		t[i] ^= b
+	}
+
+	// k' = hash_nonce(t || bytes(P) || m) mod n
+	r, err := taggedHash("BIP0340/nonce", t, pk, m)
+	if err != nil {
+		return nil, err
+	}
+	k := new(big.Int).SetBytes(r)
+	k.Mod(k, n)
+	if k.Sign() == 0 {
+		return nil, errors.New("invalid nonce")
+	}
+	R := c.NewPoint().MulSecret(g, k, n)
+	if hasEvenY(R) == false {
+		k.Sub(n, k)
+	}
+	rx := bytes32(R.GetX())
+
+	// e = hash_challenge(bytes(R) || bytes(P) || m) mod n
+	h, err := taggedHash("BIP0340/challenge", rx, pk, m)
+	if err != nil {
+		return nil, err
+	}
+	e := new(big.Int).SetBytes(h)
+	e.Mod(e, n)
+
+	// s = (k + e*d) mod n
+	s := e.Mul(e, d)
+	s.Add(s, k)
+	s.Mod(s, n)
+	sig := append(rx, bytes32(s)...)
+
+	// make sure we are not about to emit a bogus signature.
+	ok, err := Verify(pk, m, sig)
+	if err != nil {
+		return nil, err
+	} else if ok == false {
+		return nil, errors.New("signature verification failed")
+	}
+
+	return sig, nil
+}
+
+// Verify() checks if sig is a valid signature of m by the x-only public
+// key pk.
+func Verify(pk, m, sig []byte) (bool, error) {
+	cp := secp256k1.Params
+	if len(pk) != KeyLen {
+		return false, ErrBadKey
+	}
+	if len(sig) != SigLen {
+		return false, ErrBadSig
+	}
+	P, err := liftX(new(big.Int).SetBytes(pk))
+	if err != nil {
+		return false, err
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	if r.Cmp(cp.P) != -1 || s.Cmp(cp.N) != -1 {
+		return false, nil
+	}
+
+	// e = hash_challenge(bytes(r) || bytes(P) || m) mod n
+	h, err := taggedHash("BIP0340/challenge", sig[:32], pk, m)
+	if err != nil {
+		return false, err
+	}
+	e := new(big.Int).SetBytes(h)
+	e.Mod(e, cp.N)
+
+	// R = s*G - e*P
+	_, c, g, err := cp.Get()
+	if err != nil {
+		return false, err
+	}
+	sG := c.NewPoint().Mul(g, s)
+	eP := c.NewPoint().Mul(P, e)
+	R := c.NewPoint().Add(sG, c.NewPoint().Neg(eP))
+	if c.IsInf(R) || hasEvenY(R) == false || R.GetX().Cmp(r) != 0 {
+		return false, nil
+	}
+
+	return true, nil
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"godot/ecdsa/sec1"
+	"godot/ecdsa/secp256k1"
+	"godot/rand"
+	"godot/schnorr/bip340"
+	"godot/sha256"
+	"godot/util"
+	"io"
+	"math/big"
+)
+
+type schnorr struct {
+	Private *big.Int
+	Public  []byte // x-only public key
+}
+
+func New() *schnorr {
+	return new(schnorr)
+}
+
+// Option() parses Schnorr-specific options. None are currently defined.
+func (sc *schnorr) Option(op string, args []string, i *int) bool {
+	return false
+}
+
+// Describe() returns the names of the signature algorithm and hash, as
+// recorded in armored signatures.
+func (sc *schnorr) Describe() (alg, hash string) {
+	return "BIP-340", "SHA-256"
+}
+
+// NewKey() creates a new secp256k1 key pair and writes it to w in PEM
+// format. The key size is fixed, so l must be zero.
+func (sc *schnorr) NewKey(l int, w io.Writer) error {
+	if l != 0 {
+		return errors.New("key size is fixed")
+	}
+	cp := secp256k1.Params
+	q, d, err := cp.NewPair()
+	if err != nil {
+		return err
+	}
+	k := new(sec1.PrivateKey)
+	err = k.SetCurve(&cp.OID)
+	if err != nil {
+		return err
+	}
+	err = k.SetPoint(q.GetX(), q.GetY(), cp.ByteLen())
+	if err != nil {
+		return err
+	}
+	err = k.SetGenerator(d, cp.ScalarLen())
+	if err != nil {
+		return err
+	}
+	sc.Private = d
+
+	return k.Write(w)
+}
+
+// LoadPriv() loads a secp256k1 private key from r.
+func (sc *schnorr) LoadPriv(r io.Reader) error {
+	k, err := new(sec1.PrivateKey).Read(r)
+	if err != nil {
+		return err
+	}
+	id, err := k.GetCurveID()
+	if err != nil {
+		return err
+	}
+	if secp256k1.OID.Equal(*id) == false {
+		return errors.New("unsupported curve")
+	}
+	err = k.Validate(secp256k1.Params)
+	if err != nil {
+		return err
+	}
+	d, err := k.GetGenerator()
+	if err != nil {
+		return err
+	}
+	sc.Private = d
+
+	return nil
+}
+
+// LoadPub() loads a public key from r. Both hex-encoded x-only keys
+// and secp256k1 X.509 public keys, in PEM or DER format, are accepted.
+func (sc *schnorr) LoadPub(r io.Reader) error {
+	body := util.ReadAll(r)
+	pk, err := hex.DecodeString(string(bytes.TrimSpace(body)))
+	if err != nil {
+		k, err := new(sec1.PublicKey).Read(bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		if secp256k1.OID.Equal(*k.GetCurveID()) == false {
+			return errors.New("unsupported curve")
+		}
+		x, _, err := k.GetPoint(secp256k1.Params)
+		if err != nil {
+			return err
+		}
+		sc.Public = make([]byte, bip340.KeyLen)
+		copy(sc.Public[bip340.KeyLen - len(x.Bytes()):], x.Bytes())
+		return nil
+	}
+	if len(pk) != bip340.KeyLen {
+		return bip340.ErrBadKey
+	}
+	sc.Public = pk
+
+	return nil
+}
+
+// WritePub() writes a hex-encoded x-only public key to w.
+func (sc *schnorr) WritePub(w io.Writer) error {
+	pk, err := bip340.Public(sc.Private)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%x\n", pk)
+
+	return err
+}
+
+// Sign() generates a signature of the SHA-256 digest of m and writes
+// it to w.
+func (sc *schnorr) Sign(m io.Reader, w io.Writer) error {
+	h, err := sha256.DigestAll(m)
+	if err != nil {
+		return err
+	}
+	aux, err := rand.Bytes(32)
+	if err != nil {
+		return err
+	}
+	sig, err := bip340.Sign(sc.Private, h, aux)
+	if err != nil {
+		return err
+	}
+	w.Write(sig)
+
+	return nil
+}
+
+// Verify() checks if t is a valid signature of the SHA-256 digest of m.
+func (sc *schnorr) Verify(t, m io.Reader) (bool, error) {
+	h, err := sha256.DigestAll(m)
+	if err != nil {
+		return false, err
+	}
+
+	return bip340.Verify(sc.Public, h, util.ReadAll(t))
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package schnorr
+
+import (
+	"fmt"
+	"os"
+)
+
+func (sc *schnorr) UsageError() {
+	fmt.Fprintf(os.Stderr,
+`usage: godot schnorr [command] [arguments]
+
+The supported commands are:
+
+godot schnorr new [--outform <form>] [-o <file>]
+
+	Creates a new secp256k1 private key. If -o is specified, the
+	key is written to <file> instead of stdout. The key is
+	interchangeable with one created by godot ecdsa new.
+
+godot schnorr pub [-i <file>] [--inform <form>] [-o <file>]
+
+	Derives a BIP-340 x-only public key from a private key. If -i
+	is specified, the key is read from <file> instead of stdin. The
+	key must be a secp256k1 private key. If -o is specified, the
+	public key is written to <file> instead of stdout. The public
+	key is written as 64 hexadecimal digits.
+
+godot schnorr sign -k <file> [--inform <form>] [--sig-format <form>]
+    [-i <file>] [-o <file>]
+
+	Generates a BIP-340 Schnorr signature of the SHA-256 digest of
+	the contents being signed. The -k parameter must be specified,
+	and <file> must point to a secp256k1 private key. If -i is
+	specified, the contents to be signed are read from <file>
+	instead of stdin. If -o is specified, the resulting signature
+	is written to <file> instead of stdout.
+
+godot schnorr verify -k <file> [--inform <form>] -s <file> [-i <file>]
+
+	Verifies a BIP-340 Schnorr signature of the SHA-256 digest of
+	the data being verified. The -k and -s parameters must be
+	specified and must point to a public key and signature
+	respectively. The public key may be either a hex-encoded x-only
+	key or a secp256k1 public key as written by godot ecdsa pub. If
+	-i is specified, the data whose signature is being verified is
+	read from <file> instead of stdin.
+
+--inform and --outform select the form in which keys are read and
+written: der or pem. Private keys are written in PEM format unless
+--outform der is specified, and keys are read in either format unless
+--inform is specified.
+
+--sig-format selects the form in which signatures are written: binary,
+hex, base64, or pem for a "GODOT SIGNATURE" that also records the
+algorithm and hash used. Signatures are written in binary format
+unless --sig-format is specified, and read in any of these forms; the
+algorithm and hash recorded in a "GODOT SIGNATURE" must match.
+
+--{in,key,out,sig} can be used instead of -{i,k,o,s}.
+`)
+	os.Exit(1)
+}
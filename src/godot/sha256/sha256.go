@@ -129,7 +129,7 @@ func hash(h [8]uint32, chunk []byte) ([8]uint32, error) {
 func wrap(chunk []byte, totalRounds int) []byte {
 	padding := make([]byte, padLen)
 	padding[0] = 0x80 // one bit set followed by seven bits unset
-	z := uint64((56 - len(chunk))) % 64 // followed by z zero bytes
+	z := uint64((55 - len(chunk))) % 64 + 1 // z - 1 zero bytes
 	t := uint64((len(chunk) + (totalRounds * chunkLen)) * 8)
 	for i := uint64(0); i < 8; i++ {
 		// total msg len in bits, in big-endian notation
@@ -0,0 +1,205 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+//
+// This is a implementation of SHA-512 as defined in FIPS 180-4.
+
+package sha512
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+const (
+	chunkLen = 1 << 13  // how much we try to read in one loop
+	maxRounds = 1 << 48 // maximum number of times we will loop
+	padLen = 144        // bytes reserved for padding
+	Len = 64            // bytes in a SHA-512 digest
+)
+
+var shaK = [80]uint64 {
+	0x428a2f98d728ae22, 0x7137449123ef65cd, 0xb5c0fbcfec4d3b2f,
+	0xe9b5dba58189dbbc, 0x3956c25bf348b538, 0x59f111f1b605d019,
+	0x923f82a4af194f9b, 0xab1c5ed5da6d8118, 0xd807aa98a3030242,
+	0x12835b0145706fbe, 0x243185be4ee4b28c, 0x550c7dc3d5ffb4e2,
+	0x72be5d74f27b896f, 0x80deb1fe3b1696b1, 0x9bdc06a725c71235,
+	0xc19bf174cf692694, 0xe49b69c19ef14ad2, 0xefbe4786384f25e3,
+	0x0fc19dc68b8cd5b5, 0x240ca1cc77ac9c65, 0x2de92c6f592b0275,
+	0x4a7484aa6ea6e483, 0x5cb0a9dcbd41fbd4, 0x76f988da831153b5,
+	0x983e5152ee66dfab, 0xa831c66d2db43210, 0xb00327c898fb213f,
+	0xbf597fc7beef0ee4, 0xc6e00bf33da88fc2, 0xd5a79147930aa725,
+	0x06ca6351e003826f, 0x142929670a0e6e70, 0x27b70a8546d22ffc,
+	0x2e1b21385c26c926, 0x4d2c6dfc5ac42aed, 0x53380d139d95b3df,
+	0x650a73548baf63de, 0x766a0abb3c77b2a8, 0x81c2c92e47edaee6,
+	0x92722c851482353b, 0xa2bfe8a14cf10364, 0xa81a664bbc423001,
+	0xc24b8b70d0f89791, 0xc76c51a30654be30, 0xd192e819d6ef5218,
+	0xd69906245565a910, 0xf40e35855771202a, 0x106aa07032bbd1b8,
+	0x19a4c116b8d2d0c8, 0x1e376c085141ab53, 0x2748774cdf8eeb99,
+	0x34b0bcb5e19b48a8, 0x391c0cb3c5c95a63, 0x4ed8aa4ae3418acb,
+	0x5b9cca4f7763e373, 0x682e6ff3d6b2b8a3, 0x748f82ee5defb2fc,
+	0x78a5636f43172f60, 0x84c87814a1f0ab72, 0x8cc702081a6439ec,
+	0x90befffa23631e28, 0xa4506cebde82bde9, 0xbef9a3f7b2c67915,
+	0xc67178f2e372532b, 0xca273eceea26619c, 0xd186b8c721c0c207,
+	0xeada7dd6cde0eb1e, 0xf57d4f7fee6ed178, 0x06f067aa72176fba,
+	0x0a637dc5a2c898a6, 0x113f9804bef90dae, 0x1b710b35131c471b,
+	0x28db77f523047d84, 0x32caab7b40c72493, 0x3c9ebe0a15c9bebc,
+	0x431d67c49c100d4c, 0x4cc5d4becb3e42b6, 0x597f299cfc657e2a,
+	0x5fcb6fab3ad6faec, 0x6c44198c4a475817,
+}
+
+var shaH = [8]uint64 {
+	0x6a09e667f3bcc908, 0xbb67ae8584caa73b,
+	0x3c6ef372fe94f82b, 0xa54ff53a5f1d36f1,
+	0x510e527fade682d1, 0x9b05688c2b3e6c1f,
+	0x1f83d9abfb41bd6b, 0x5be0cd19137e2179,
+}
+
+func ch(x, y, z uint64) uint64 {
+	return (x & y) ^ (^x & z)
+}
+
+func maj(x, y, z uint64) uint64 {
+	return (x & y) ^ (x & z) ^ (y & z)
+}
+
+func rotr(x, n uint64) uint64 {
+	return (x >> n) | (x << (64 - n))
+}
+
+func upperSigma0(x uint64) uint64 {
+	return rotr(x, 28) ^ rotr(x, 34) ^ rotr(x, 39)
+}
+
+func upperSigma1(x uint64) uint64 {
+	return rotr(x, 14) ^ rotr(x, 18) ^ rotr(x, 41)
+}
+
+func lowerSigma0(x uint64) uint64 {
+	return rotr(x, 1) ^ rotr(x, 8) ^ (x >> 7)
+}
+
+func lowerSigma1(x uint64) uint64 {
+	return rotr(x, 19) ^ rotr(x, 61) ^ (x >> 6)
+}
+
+func sha512(h [8]uint64, m []uint64) [8]uint64 {
+	var l = make([]uint64, 8)
+	var w = make([]uint64, 80)
+
+	copy(w, m)
+
+	for t := 16; t < 80; t++ {
+		w[t] = lowerSigma1(w[t - 2]) + w[t - 7] +
+		    lowerSigma0(w[t - 15]) + w[t - 16]
+	}
+
+	for i := 0; i < 8; i++ {
+		l[i] = h[i]
+	}
+
+	for t := 0; t < 80; t++ {
+		t1 := l[7] + upperSigma1(l[4]) + ch(l[4], l[5], l[6]) +
+		    shaK[t] + w[t]
+		t2 := upperSigma0(l[0]) + maj(l[0], l[1], l[2])
+		l[7] = l[6]
+		l[6] = l[5]
+		l[5] = l[4]
+		l[4] = l[3] + t1
+		l[3] = l[2]
+		l[2] = l[1]
+		l[1] = l[0]
+		l[0] = t1 + t2
+	}
+
+	for i := 0; i < 8; i++ {
+		h[i] += l[i]
+	}
+
+	return h
+}
+
+func hash(h [8]uint64, chunk []byte) ([8]uint64, error) {
+	m := make([]uint64, len(chunk) / 8)
+	err := binary.Read(bytes.NewBuffer(chunk), binary.BigEndian, m)
+	if err != nil {
+		return h, err
+	}
+	for i := 0; i < len(m); i += 16 {
+		h = sha512(h, m[i:(i+16)])
+	}
+
+	return h, nil
+}
+
+// wrap() pads the last segment of a message being hashed. The
+// message length is encoded in 128 bits, of which we only ever
+// use the lower 64.
+func wrap(chunk []byte, totalRounds int) []byte {
+	padding := make([]byte, padLen)
+	padding[0] = 0x80 // one bit set followed by seven bits unset
+	z := uint64((111 - len(chunk))) % 128 + 1 // z - 1 zero bytes
+	t := uint64((len(chunk) + (totalRounds * chunkLen)) * 8)
+	for i := uint64(0); i < 8; i++ {
+		// total msg len in bits, in big-endian notation
+		padding[z + 8 + i] = byte((t >> (8 * (7 - i))) & 0xff)
+	}
+
+	return append(chunk, padding[:(z + 16)]...)
+}
+
+// toByteSlice() transforms a [8]uint64 into a []byte.
+func toByteSlice(h [8]uint64) ([]byte, error) {
+	r := bytes.NewBuffer(make([]byte, 0, Len))
+	err := binary.Write(r, binary.BigEndian, h)
+	if err != nil {
+		return nil, err
+	}
+	return r.Bytes(), nil
+}
+
+// DigestAll() returns a digest of the contents of r.
+func DigestAll(r io.Reader) ([]byte, error) {
+	var chunk = make([]byte, chunkLen)
+	var h = shaH
+	var i, n int
+	var err error
+
+	in := bufio.NewReader(r)
+	for i = 0; i < maxRounds && err == nil; i++ {
+		n, err = io.ReadFull(in, chunk)
+		if err != nil && err != io.EOF &&
+		   err != io.ErrUnexpectedEOF {
+			return nil, err
+		} else if n != chunkLen {
+			h, err = hash(h, wrap(chunk[:n], i))
+			break
+		} else {
+			h, err = hash(h, chunk)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	} else if i == maxRounds {
+		return nil, errors.New("input too long")
+	}
+
+	return toByteSlice(h)
+}
+
+// DigestBytes() returns a digest of the bytes pointed to by p.
+func DigestBytes(p []byte) ([]byte, error) {
+	if len(p) > (1 << 61) - 1 {
+		return nil, errors.New("input too long")
+	}
+	h, err := hash(shaH, wrap(p, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	return toByteSlice(h)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package util
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// readLine() reads a line from r, without the trailing newline.
+func readLine(r io.Reader) []byte {
+	line, err := bufio.NewReader(r).ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	return bytes.TrimRight(line, "\r\n")
+}
+
+// prompt() asks for a passphrase on the terminal, with echo disabled.
+func prompt(msg string) []byte {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	defer tty.Close()
+
+	stty := func(arg string) {
+		cmd := exec.Command("stty", arg)
+		cmd.Stdin = tty
+		cmd.Run()
+	}
+	stty("-echo")
+	fmt.Fprintf(tty, "%s", msg)
+	pass := readLine(tty)
+	fmt.Fprintf(tty, "\n")
+	stty("echo")
+
+	return pass
+}
+
+// ReadPass() obtains a passphrase from src, which is either env:<var>
+// (the environment variable <var>), fd:<n> (the first line read from
+// file descriptor <n>), or empty, in which case the user is prompted
+// on the terminal. If confirm is true, the user is prompted twice.
+func ReadPass(src string, confirm bool) []byte {
+	var pass []byte
+
+	switch {
+	case strings.HasPrefix(src, "env:"):
+		v, ok := os.LookupEnv(src[4:])
+		if ok == false {
+			fmt.Fprintf(os.Stderr, "%s is not set\n", src[4:])
+			os.Exit(1)
+		}
+		pass = []byte(v)
+	case strings.HasPrefix(src, "fd:"):
+		fd, err := strconv.Atoi(src[3:])
+		if err != nil || fd < 0 {
+			fmt.Fprintf(os.Stderr, "invalid file descriptor\n")
+			os.Exit(1)
+		}
+		pass = readLine(os.NewFile(uintptr(fd), src))
+	case src == "":
+		pass = prompt("Enter passphrase: ")
+		if confirm && bytes.Equal(pass,
+		    prompt("Confirm passphrase: ")) == false {
+			fmt.Fprintf(os.Stderr, "passphrases do not match\n")
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "invalid passphrase source\n")
+		os.Exit(1)
+	}
+	if len(pass) == 0 {
+		fmt.Fprintf(os.Stderr, "empty passphrase\n")
+		os.Exit(1)
+	}
+
+	return pass
+}
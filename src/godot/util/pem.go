@@ -0,0 +1,76 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package util
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// IsPEM() tells whether body looks PEM-encoded.
+func IsPEM(body []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("-----BEGIN"))
+}
+
+// Unarmor() returns the contents of the PEM block in body, and the
+// block's type. If body is not PEM-encoded, it is taken to be DER and
+// returned as is, with an empty type. ok is false if body looks like
+// PEM but cannot be decoded.
+func Unarmor(body []byte) (der []byte, t string, ok bool) {
+	if IsPEM(body) == false {
+		return body, "", len(body) != 0
+	}
+	blob, _ := pem.Decode(body)
+	if blob == nil {
+		return nil, "", false
+	}
+
+	return blob.Bytes, blob.Type, true
+}
+
+// CheckForm() ensures body is in the given form: "der", "pem", or ""
+// if either is acceptable.
+func CheckForm(body []byte, form string) error {
+	switch {
+	case form == "der" && IsPEM(body):
+		return errors.New("expected DER, got PEM")
+	case form == "pem" && IsPEM(body) == false:
+		return errors.New("expected PEM, got DER")
+	}
+
+	return nil
+}
+
+// WriteForm() writes the PEM-encoded body to w in the given form:
+// "der" strips the PEM armor, while "pem" or "" leave body untouched.
+func WriteForm(w io.Writer, body []byte, form string) error {
+	if form == "der" {
+		blob, _ := pem.Decode(body)
+		if blob == nil {
+			return errors.New("output cannot be written as DER")
+		}
+		body = blob.Bytes
+	}
+	_, err := w.Write(body)
+
+	return err
+}
+
+// GetFormArg() retrieves a "der" or "pem" token from 'args' at index
+// i + 1. The token must exist.
+func GetFormArg(args []string, i *int) string {
+	opt := args[*i]
+	form := GetArg(args, i)
+	if form != "der" && form != "pem" {
+		fmt.Fprintf(os.Stderr, "option %s requires der or pem\n", opt)
+		os.Exit(1)
+	}
+
+	return form
+}
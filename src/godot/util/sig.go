@@ -0,0 +1,119 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package util
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	ErrSigAlg  = errors.New("signature made with a different algorithm")
+	ErrArmored = errors.New("--sig-format cannot be used with armored " +
+	    "signatures")
+)
+
+// The PEM type of an armored signature. The block's headers record
+// the algorithm and hash the signature was made with.
+const SigPemType = "GODOT SIGNATURE"
+
+// WriteSig() writes the signature sig to w in the given form: "hex",
+// "base64", "pem", or "binary" or "" for sig as is. alg and hash are
+// recorded in the headers of a "pem" signature. Signatures that come
+// armored, such as sshsig ones, can only be written as they are.
+func WriteSig(w io.Writer, sig []byte, form, alg, hash string) error {
+	var err error
+
+	if IsPEM(sig) && form != "" && form != "binary" {
+		return ErrArmored
+	}
+
+	switch form {
+	case "hex":
+		_, err = fmt.Fprintf(w, "%s\n", hex.EncodeToString(sig))
+	case "base64":
+		_, err = fmt.Fprintf(w, "%s\n",
+		    base64.StdEncoding.EncodeToString(sig))
+	case "pem":
+		err = pem.Encode(w, &pem.Block{Type: SigPemType,
+		    Headers: map[string]string{"Algorithm": alg, "Hash": hash},
+		    Bytes: sig})
+	default:
+		_, err = w.Write(sig)
+	}
+
+	return err
+}
+
+// isHex() tells whether body is a non-empty string of hex digit pairs.
+func isHex(body []byte) bool {
+	if len(body) == 0 || len(body) % 2 != 0 {
+		return false
+	}
+	for _, c := range body {
+		if bytes.IndexByte([]byte("0123456789abcdefABCDEF"), c) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ReadSig() decodes a signature written by WriteSig(), detecting its
+// form. Signatures that are not armored as a SigPemType, hex-encoded,
+// or base64-encoded are returned as is, so that binary and other
+// armored signatures are left for the algorithm to read. If the
+// signature is armored, the recorded algorithm and hash must match
+// alg and hash.
+func ReadSig(r io.Reader, alg, hash string) ([]byte, error) {
+	body := ReadAll(r)
+	text := bytes.TrimSpace(body)
+	if bytes.HasPrefix(text, []byte("-----BEGIN " + SigPemType)) {
+		blob, _ := pem.Decode(text)
+		if blob == nil || blob.Type != SigPemType {
+			return nil, errors.New("invalid armored signature")
+		}
+		if blob.Headers["Algorithm"] != alg ||
+		   blob.Headers["Hash"] != hash {
+			return nil, ErrSigAlg
+		}
+		return blob.Bytes, nil
+	}
+	if isHex(text) {
+		sig, _ := hex.DecodeString(string(text))
+		return sig, nil
+	}
+	b64 := bytes.Join(bytes.Fields(text), nil)
+	if len(b64) != 0 {
+		sig, err := base64.StdEncoding.DecodeString(string(b64))
+		if err == nil {
+			return sig, nil
+		}
+	}
+
+	return body, nil
+}
+
+// GetSigFormArg() retrieves a "binary", "hex", "base64" or "pem" token
+// from 'args' at index i + 1. The token must exist.
+func GetSigFormArg(args []string, i *int) string {
+	opt := args[*i]
+	form := GetArg(args, i)
+	switch form {
+	case "binary", "hex", "base64", "pem":
+		return form
+	}
+	fmt.Fprintf(os.Stderr, "option %s requires binary, hex, base64 or " +
+	    "pem\n", opt)
+	os.Exit(1)
+
+	return ""
+}
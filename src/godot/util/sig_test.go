@@ -0,0 +1,96 @@
+// Copyright (c) 2016 Pedro Martelletto. All rights reserved.
+// Use of this source code is governed by a BSD-style license
+// that can be found in the LICENSE file.
+
+package util
+
+import (
+	"bytes"
+	"testing"
+)
+
+// An armored signature, as produced by ssh-keygen -Y sign (truncated).
+const sshsig = `-----BEGIN SSH SIGNATURE-----
+U1NIU0lHAAAAAQAAAZcAAAAHc3NoLXJzYQAAAAMBAAEAAAGBAMMbmQj2Lm2DgnlAmNUAgn
+-----END SSH SIGNATURE-----
+`
+
+func TestRoundTrip(t *testing.T) {
+	sigs := [][]byte{
+		{0x30, 0x44, 0x02, 0x20, 0x0a, 0xff, 0x00, 0x7e},
+		[]byte("abcd"),
+		[]byte("0123456789abcdef"),
+		bytes.Repeat([]byte{0xa5}, 64),
+	}
+
+	for i, sig := range sigs {
+		for _, form := range []string{"binary", "hex", "base64",
+		    "pem"} {
+			w := new(bytes.Buffer)
+			err := WriteSig(w, sig, form, "ecdsa", "sha256")
+			if err != nil {
+				t.Fatalf("sig %d, %s: %v", i, form, err)
+			}
+			if form == "binary" {
+				// Binary signatures that happen to look
+				// like hex or base64 are ambiguous.
+				continue
+			}
+			p, err := ReadSig(w, "ecdsa", "sha256")
+			if err != nil {
+				t.Fatalf("sig %d, %s: %v", i, form, err)
+			}
+			if bytes.Equal(p, sig) == false {
+				t.Errorf("sig %d, %s: got %x", i, form, p)
+			}
+		}
+	}
+}
+
+func TestBinary(t *testing.T) {
+	sig := []byte{0x30, 0x44, 0x02, 0x20, 0x0a, 0xff, 0x00, 0x7e}
+	p, err := ReadSig(bytes.NewReader(sig), "ecdsa", "sha256")
+	if err != nil || bytes.Equal(p, sig) == false {
+		t.Errorf("got %x, %v", p, err)
+	}
+}
+
+// The algorithm and hash recorded in a "pem" signature must match.
+func TestSigAlg(t *testing.T) {
+	w := new(bytes.Buffer)
+	err := WriteSig(w, []byte{1, 2, 3}, "pem", "ed25519", "sha512")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ReadSig(bytes.NewReader(w.Bytes()), "ecdsa", "sha512")
+	if err != ErrSigAlg {
+		t.Errorf("algorithm: got %v, want %v", err, ErrSigAlg)
+	}
+	_, err = ReadSig(bytes.NewReader(w.Bytes()), "ed25519", "sha256")
+	if err != ErrSigAlg {
+		t.Errorf("hash: got %v, want %v", err, ErrSigAlg)
+	}
+}
+
+// Armored signatures are neither wrapped again when written, nor
+// decoded when read.
+func TestArmored(t *testing.T) {
+	for _, form := range []string{"", "binary"} {
+		w := new(bytes.Buffer)
+		err := WriteSig(w, []byte(sshsig), form, "rsa", "sha512")
+		if err != nil || w.String() != sshsig {
+			t.Errorf("%q: got %q, %v", form, w.String(), err)
+		}
+	}
+	for _, form := range []string{"hex", "base64", "pem"} {
+		w := new(bytes.Buffer)
+		err := WriteSig(w, []byte(sshsig), form, "rsa", "sha512")
+		if err != ErrArmored || w.Len() != 0 {
+			t.Errorf("%s: got %q, %v", form, w.String(), err)
+		}
+	}
+	p, err := ReadSig(bytes.NewReader([]byte(sshsig)), "rsa", "sha512")
+	if err != nil || string(p) != sshsig {
+		t.Errorf("read: got %q, %v", p, err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"strconv"
 )
 
 // CreateFile() creates a new, write-only, chmod 0600 file. Its first
@@ -86,6 +87,20 @@ func GetArg(args []string, i *int) string {
 	return args[*i]
 }
 
+// GetIntArg() retrieves an integer token from 'args' at index i + 1.
+// The token must exist.
+func GetIntArg(args []string, i *int) int {
+	opt := args[*i]
+	v, err := strconv.Atoi(GetArg(args, i))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "option %s requires an integer " +
+		    "argument\n", opt)
+		os.Exit(1);
+	}
+
+	return v
+}
+
 // ReadAll() is a simple wrapper around ioutil.ReadAll().
 func ReadAll(r io.Reader) []byte {
 	body, err := ioutil.ReadAll(r)